@@ -0,0 +1,215 @@
+package main
+
+import "fmt"
+
+type ActionStatus int
+
+const (
+	ActionRunning ActionStatus = iota
+	ActionDone
+	ActionFailed
+)
+
+// Action is one step of something an entity is doing: walking somewhere,
+// chopping a tree, fighting. Actions are chained in an ActionQueue so "walk
+// over there, then do this" is just two actions in a row.
+type Action interface {
+	// Start is called once when the action reaches the front of the queue.
+	// Returning false fails it straight away.
+	Start() bool
+	// Update advances the action by one simulation step and reports whether
+	// it is still running. Timed work should count t.Game ticks.
+	Update(t Tick) ActionStatus
+	// Cancel undoes any state the action left on its entity. It is called
+	// when a started action is interrupted or fails.
+	Cancel()
+	// Complete applies the action's result once Update reports ActionDone.
+	Complete()
+	// Label is the status text shown while the action runs, if any.
+	Label() string
+}
+
+// ActionQueue runs one entity's actions in order. A failed action drops the
+// rest of the queue, since later steps usually depend on it.
+type ActionQueue struct {
+	actions []Action
+	started bool
+}
+
+func (q *ActionQueue) Push(actions ...Action) {
+	q.actions = append(q.actions, actions...)
+}
+
+// Replace cancels whatever is running and queues actions in its place. This
+// is what a fresh click does.
+func (q *ActionQueue) Replace(actions ...Action) {
+	q.Clear()
+	q.Push(actions...)
+}
+
+func (q *ActionQueue) Clear() {
+	if q.started && len(q.actions) > 0 {
+		q.actions[0].Cancel()
+	}
+	q.actions = nil
+	q.started = false
+}
+
+func (q *ActionQueue) Current() Action {
+	if len(q.actions) == 0 {
+		return nil
+	}
+	return q.actions[0]
+}
+
+func (q *ActionQueue) Idle() bool {
+	return len(q.actions) == 0
+}
+
+func (q *ActionQueue) Label() string {
+	if a := q.Current(); a != nil && q.started {
+		return a.Label()
+	}
+	return ""
+}
+
+func (q *ActionQueue) Update(t Tick) {
+	for len(q.actions) > 0 {
+		a := q.actions[0]
+
+		if !q.started {
+			q.started = true
+			if !a.Start() {
+				q.Clear()
+				return
+			}
+		}
+
+		switch a.Update(t) {
+		case ActionRunning:
+			return
+		case ActionFailed:
+			q.Clear()
+			return
+		case ActionDone:
+			a.Complete()
+			q.actions = q.actions[1:]
+			q.started = false
+			// Let the next action start this step, but without
+			// spending the same time twice.
+			t.Dt = 0
+			t.Game = false
+		}
+	}
+}
+
+// WalkAction walks the player to Target, or to any walkable tile next to it
+// when Adjacent is set (for trees, enemies and anything else you stand beside).
+type WalkAction struct {
+	p        *Player
+	Target   Point
+	Adjacent bool
+	Step     bool // a single keyboard or gamepad step
+	Range    int  // stop early once within this many tiles with a clear line of sight
+	dest     Point
+}
+
+func NewWalkAction(p *Player, target Point) *WalkAction {
+	return &WalkAction{p: p, Target: target}
+}
+
+func NewWalkAdjacentAction(p *Player, target Point) *WalkAction {
+	return &WalkAction{p: p, Target: target, Adjacent: true}
+}
+
+func NewWalkInRangeAction(p *Player, target Point, tiles int) *WalkAction {
+	return &WalkAction{p: p, Target: target, Range: tiles}
+}
+
+func NewStepAction(p *Player, target Point) *WalkAction {
+	return &WalkAction{p: p, Target: target, Step: true}
+}
+
+func (w *WalkAction) arrived() bool {
+	here := w.p.CurrentTile()
+	if w.Range > 0 && max(abs(here.X-w.Target.X), abs(here.Y-w.Target.Y)) <= w.Range && w.p.Map.LineOfSight(here, w.Target) {
+		return true
+	}
+	if w.Adjacent {
+		return adjacent(here, w.Target)
+	}
+	return here == w.dest
+}
+
+func (w *WalkAction) Start() bool {
+	w.dest = w.Target
+	if w.arrived() {
+		return true
+	}
+
+	if w.Adjacent {
+		adj := w.p.FindAdjacentWalkable(w.Target)
+		if adj == nil {
+			fmt.Println("No adjacent walkable tile to", w.Target)
+			return false
+		}
+		w.dest = *adj
+	}
+	return w.p.MoveToTile(w.dest.X, w.dest.Y)
+}
+
+func (w *WalkAction) Update(t Tick) ActionStatus {
+	if w.Range > 0 && w.arrived() {
+		// Finish the step in progress so the player stops on a tile.
+		if len(w.p.Path) > 1 {
+			w.p.Path = w.p.Path[:1]
+		}
+		return ActionDone
+	}
+	if len(w.p.Path) > 0 {
+		return ActionRunning
+	}
+	if w.arrived() {
+		return ActionDone
+	}
+	// The path ran out without getting there, so repair gave up.
+	return ActionFailed
+}
+
+func (w *WalkAction) Cancel() {
+	w.p.Path = nil
+}
+
+func (w *WalkAction) Complete() {}
+
+func (w *WalkAction) Label() string { return "" }
+
+// InteractAction runs Func against a tile the player is standing next to.
+// Doors, NPCs and other one-shot interactions plug in here.
+type InteractAction struct {
+	p      *Player
+	Target Point
+	Text   string
+	Func   func(p *Player, target Point) bool
+}
+
+func NewInteractAction(p *Player, target Point, text string, fn func(p *Player, target Point) bool) *InteractAction {
+	return &InteractAction{p: p, Target: target, Text: text, Func: fn}
+}
+
+func (a *InteractAction) Start() bool {
+	return adjacent(a.p.CurrentTile(), a.Target)
+}
+
+func (a *InteractAction) Update(t Tick) ActionStatus {
+	if a.Func(a.p, a.Target) {
+		return ActionDone
+	}
+	return ActionFailed
+}
+
+func (a *InteractAction) Cancel() {}
+
+func (a *InteractAction) Complete() {}
+
+func (a *InteractAction) Label() string { return a.Text }
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// fakeAction logs each call it gets, runs for a set number of updates and
+// then ends with status.
+type fakeAction struct {
+	name   string
+	log    *[]string
+	start  bool
+	steps  int
+	status ActionStatus
+}
+
+func (a *fakeAction) record(call string) { *a.log = append(*a.log, a.name+"."+call) }
+
+func (a *fakeAction) Start() bool {
+	a.record("Start")
+	return a.start
+}
+
+func (a *fakeAction) Update(t Tick) ActionStatus {
+	a.record("Update")
+	if a.steps > 0 {
+		a.steps--
+		return ActionRunning
+	}
+	return a.status
+}
+
+func (a *fakeAction) Cancel()       { a.record("Cancel") }
+func (a *fakeAction) Complete()     { a.record("Complete") }
+func (a *fakeAction) Label() string { return a.name }
+
+func TestActionQueue(t *testing.T) {
+	tests := []struct {
+		name    string
+		first   fakeAction
+		replace bool // Replace with a fresh action after one update
+		want    []string
+	}{
+		{
+			name:  "done runs the next",
+			first: fakeAction{start: true, status: ActionDone},
+			want:  []string{"a.Start", "a.Update", "a.Complete", "b.Start", "b.Update", "b.Update"},
+		},
+		{
+			name:  "failed Start drops the rest",
+			first: fakeAction{start: false},
+			want:  []string{"a.Start", "a.Cancel"},
+		},
+		{
+			name:  "failed Update drops the rest",
+			first: fakeAction{start: true, steps: 1, status: ActionFailed},
+			want:  []string{"a.Start", "a.Update", "a.Update", "a.Cancel"},
+		},
+		{
+			name:    "Replace cancels the running action",
+			first:   fakeAction{start: true, steps: 5},
+			replace: true,
+			want:    []string{"a.Start", "a.Update", "a.Cancel", "c.Start", "c.Update"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var log []string
+			a := tt.first
+			a.name, a.log = "a", &log
+			b := &fakeAction{name: "b", log: &log, start: true, steps: 10}
+
+			var q ActionQueue
+			q.Push(&a, b)
+			q.Update(Tick{})
+			if tt.replace {
+				q.Replace(&fakeAction{name: "c", log: &log, start: true, steps: 10})
+			}
+			q.Update(Tick{})
+
+			if !slices.Equal(log, tt.want) {
+				t.Fatalf("calls %v, want %v", log, tt.want)
+			}
+		})
+	}
+}
+
+func TestActionQueueClear(t *testing.T) {
+	var log []string
+	var q ActionQueue
+	q.Push(&fakeAction{name: "a", log: &log, start: true, steps: 5})
+
+	// Nothing has started yet, so there is nothing to cancel.
+	q.Clear()
+	if len(log) != 0 || !q.Idle() {
+		t.Fatalf("Clear before starting: calls %v, idle %v", log, q.Idle())
+	}
+
+	q.Push(&fakeAction{name: "b", log: &log, start: true, steps: 5})
+	q.Update(Tick{})
+	if got := q.Label(); got != "b" {
+		t.Fatalf("Label = %q while b runs", got)
+	}
+	q.Clear()
+	if want := []string{"b.Start", "b.Update", "b.Cancel"}; !slices.Equal(log, want) {
+		t.Fatalf("calls %v, want %v", log, want)
+	}
+	if q.Current() != nil || q.Label() != "" {
+		t.Fatalf("queue not empty after Clear: %v", q.Current())
+	}
+}
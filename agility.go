@@ -0,0 +1,272 @@
+package main
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+type ShortcutKind int
+
+const (
+	LogBalance ShortcutKind = iota
+	RopeSwing
+	WallClimb
+)
+
+// shortcutSpeeds is how fast, in pixels per second, the player moves while
+// crossing each kind of obstacle.
+var shortcutSpeeds = map[ShortcutKind]float32{
+	LogBalance: 48,
+	RopeSwing:  120,
+	WallClimb:  32,
+}
+
+// shortcutOptions is what crossing each kind of obstacle is called in the
+// right-click menu.
+var shortcutOptions = map[ShortcutKind]string{
+	LogBalance: "Walk-across",
+	RopeSwing:  "Swing-on",
+	WallClimb:  "Climb-over",
+}
+
+// Shortcut is an agility obstacle: a fixed route across terrain that can't
+// be walked, open to anyone with the Agility level. It works both ways.
+type Shortcut struct {
+	Name       string
+	Kind       ShortcutKind
+	Route      []Point // tiles crossed, from one end to the other
+	Level      int
+	XP         float64
+	FailChance float32 // at exactly the level needed
+	Damage     int     // taken on a failed attempt
+}
+
+func (s *Shortcut) Start() Point { return s.Route[0] }
+func (s *Shortcut) End() Point   { return s.Route[len(s.Route)-1] }
+
+// Cost is what taking the shortcut costs a pathfinder. It is never less than
+// the Manhattan distance between the ends, so the A* heuristic still holds.
+func (s *Shortcut) Cost() float64 {
+	return max(float64(len(s.Route)-1), heuristic(s.Start(), s.End()))
+}
+
+// FailChanceAt falls off linearly to nothing 40 levels above the requirement.
+func (s *Shortcut) FailChanceAt(level int) float32 {
+	return max(0, s.FailChance*(1-float32(level-s.Level)/40))
+}
+
+// ShortcutFrom returns the shortcut leading from a to b that a player with
+// the given Agility level can use, along with its route in that direction.
+func (m *Map) ShortcutFrom(a, b Point, agility int) (*Shortcut, []Point) {
+	for i := range m.Shortcuts {
+		s := &m.Shortcuts[i]
+		if agility < s.Level {
+			continue
+		}
+		switch {
+		case s.Start() == a && s.End() == b:
+			return s, s.Route
+		case s.End() == a && s.Start() == b:
+			route := make([]Point, len(s.Route))
+			for j, p := range s.Route {
+				route[len(route)-1-j] = p
+			}
+			return s, route
+		}
+	}
+	return nil, nil
+}
+
+// shortcutEdges returns the far ends of every shortcut from p that the given
+// Agility level allows.
+func (m *Map) shortcutEdges(p Point, agility int) []*Shortcut {
+	var out []*Shortcut
+	for i := range m.Shortcuts {
+		s := &m.Shortcuts[i]
+		if agility >= s.Level && (s.Start() == p || s.End() == p) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// ShortcutAt returns the shortcut whose route crosses tile, if any.
+func (m *Map) ShortcutAt(tile Point) *Shortcut {
+	for i := range m.Shortcuts {
+		for _, p := range m.Shortcuts[i].Route {
+			if p == tile {
+				return &m.Shortcuts[i]
+			}
+		}
+	}
+	return nil
+}
+
+// placeShortcuts lays out the agility course, carving the terrain each
+// obstacle crosses.
+func (m *Map) placeShortcuts() {
+	course := []Shortcut{
+		{"Log balance", LogBalance, []Point{{9, 8}, {10, 8}, {11, 8}, {12, 8}}, 1, 7.5, 0.15, 10},
+		{"Wall", WallClimb, []Point{{12, 12}, {12, 11}, {12, 10}}, 5, 12, 0.2, 15},
+		{"Rope swing", RopeSwing, []Point{{14, 3}, {15, 3}, {16, 3}, {17, 3}}, 10, 20, 0.25, 20},
+	}
+
+	for _, s := range course {
+		if !m.inBounds(s.Start()) || !m.inBounds(s.End()) {
+			continue
+		}
+		blocked := TileWater
+		if s.Kind == WallClimb {
+			blocked = TileWall
+		}
+		for i, p := range s.Route {
+			tile := Tile{Type: blocked}
+			if i == 0 || i == len(s.Route)-1 {
+				tile = Tile{Type: TileGrass}
+			}
+			m.Tiles[p.Y][p.X] = tile
+		}
+		if s.Kind == WallClimb {
+			// Run the wall a little either side so going round takes longer.
+			y := s.Route[1].Y
+			for x := s.Route[1].X - 2; x <= s.Route[1].X+2; x++ {
+				if m.inBounds(Point{x, y}) {
+					m.Tiles[y][x] = Tile{Type: TileWall}
+				}
+			}
+		}
+		m.Shortcuts = append(m.Shortcuts, s)
+	}
+}
+
+func (m *Map) inBounds(p Point) bool {
+	return m.GetTile(p.X, p.Y) != nil
+}
+
+func (m *Map) drawShortcuts() {
+	for _, s := range m.Shortcuts {
+		for i := 1; i < len(s.Route); i++ {
+			a := rl.NewVector2(float32(s.Route[i-1].X*TileSize+TileSize/2), float32(s.Route[i-1].Y*TileSize+TileSize/2))
+			b := rl.NewVector2(float32(s.Route[i].X*TileSize+TileSize/2), float32(s.Route[i].Y*TileSize+TileSize/2))
+			switch s.Kind {
+			case LogBalance:
+				rl.DrawLineEx(a, b, 8, rl.Brown)
+			case RopeSwing:
+				rl.DrawLineEx(a, b, 2, rl.Beige)
+			case WallClimb:
+				rl.DrawLineEx(a, b, 4, rl.DarkBrown)
+			}
+		}
+		if s.Kind == RopeSwing {
+			// The rope hangs from a post on the near bank.
+			post := s.Start()
+			rl.DrawRectangle(int32(post.X*TileSize+TileSize-6), int32(post.Y*TileSize+2), 4, TileSize-4, rl.DarkBrown)
+		}
+	}
+}
+
+// Traversal is the player partway across a shortcut. The route is fixed, so
+// it plays out like an animation rather than a walk.
+type Traversal struct {
+	Shortcut *Shortcut
+	Route    []Point // waypoints still to reach
+	From     Point   // where a failed attempt puts the player back
+	Failed   bool
+}
+
+// beginTraversal starts the player across s along route, rolling now for
+// whether they make it.
+func (p *Player) beginTraversal(s *Shortcut, route []Point) {
+	p.Traversal = &Traversal{
+		Shortcut: s,
+		Route:    route,
+		From:     route[0],
+		Failed:   sim.Rand.Float32() < s.FailChanceAt(p.Skills.Level(SkillAgility)),
+	}
+	if p.Traversal.Failed {
+		// A failed attempt gets halfway and falls back.
+		p.Traversal.Route = append([]Point{}, route[:len(route)/2+1]...)
+	}
+}
+
+// updateTraversal moves the player one step along its traversal and finishes
+// it at the end of the route.
+func (p *Player) updateTraversal(t Tick) {
+	tr := p.Traversal
+	next := tr.Route[0]
+	target := rl.NewVector2(float32(next.X*TileSize+TileSize/2)-p.Size.X/2, float32(next.Y*TileSize+TileSize/2)-p.Size.Y/2)
+
+	step := shortcutSpeeds[tr.Shortcut.Kind] * t.Dt
+	if rl.Vector2Distance(p.Pos, target) > step {
+		p.Pos = rl.Vector2Add(p.Pos, rl.Vector2Scale(rl.Vector2Normalize(rl.Vector2Subtract(target, p.Pos)), step))
+		return
+	}
+	p.Pos = target
+	tr.Route = tr.Route[1:]
+	if len(tr.Route) > 0 {
+		return
+	}
+
+	p.Traversal = nil
+	if tr.Failed {
+		p.Health = max(0, p.Health-tr.Shortcut.Damage)
+		p.Pos = rl.NewVector2(float32(tr.From.X*TileSize), float32(tr.From.Y*TileSize))
+		p.PrevPos = p.Pos
+		p.Path = nil
+		showMessage(fmt.Sprintf("You slip on the %s and hurt yourself.", tr.Shortcut.Name))
+		return
+	}
+	p.GainXP(SkillAgility, tr.Shortcut.XP)
+}
+
+// TryShortcut walks to the nearer end of a shortcut and crosses it.
+func (p *Player) TryShortcut(s *Shortcut) {
+	if level := p.Skills.Level(SkillAgility); level < s.Level {
+		showMessage(fmt.Sprintf("You need an Agility level of %d to use this %s.", s.Level, s.Name))
+		return
+	}
+
+	from, to := s.Start(), s.End()
+	here := p.CurrentTile()
+	if heuristic(here, to) < heuristic(here, from) {
+		from, to = to, from
+	}
+	p.Actions.Replace(NewWalkAction(p, from), NewShortcutAction(p, from, to))
+}
+
+// ShortcutAction crosses a shortcut from the tile the player is standing on.
+type ShortcutAction struct {
+	p        *Player
+	From, To Point
+}
+
+func NewShortcutAction(p *Player, from, to Point) *ShortcutAction {
+	return &ShortcutAction{p: p, From: from, To: to}
+}
+
+func (a *ShortcutAction) Start() bool {
+	if a.p.CurrentTile() != a.From {
+		return false
+	}
+	s, route := a.p.Map.ShortcutFrom(a.From, a.To, a.p.Skills.Level(SkillAgility))
+	if s == nil {
+		return false
+	}
+	a.p.Path = nil
+	a.p.beginTraversal(s, route)
+	return true
+}
+
+func (a *ShortcutAction) Update(t Tick) ActionStatus {
+	if a.p.Traversal != nil {
+		return ActionRunning
+	}
+	return ActionDone
+}
+
+func (a *ShortcutAction) Cancel() {}
+
+func (a *ShortcutAction) Complete() {}
+
+func (a *ShortcutAction) Label() string { return "" }
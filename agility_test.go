@@ -0,0 +1,59 @@
+package main
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestFailChanceAt(t *testing.T) {
+	s := Shortcut{Level: 20, FailChance: 0.4}
+	tests := []struct {
+		level int
+		want  float32
+	}{
+		{20, 0.4},
+		{30, 0.3},
+		{40, 0.2},
+		{59, 0.01},
+		{60, 0},
+		{99, 0},
+	}
+	for _, tt := range tests {
+		if got := s.FailChanceAt(tt.level); math.Abs(float64(got-tt.want)) > 1e-6 {
+			t.Errorf("FailChanceAt(%d) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestShortcutFrom(t *testing.T) {
+	route := []Point{{1, 0}, {1, 1}, {1, 2}, {2, 2}}
+	m := NewMap(4, 4)
+	m.Shortcuts = []Shortcut{{Name: "Log", Route: slices.Clone(route), Level: 10}}
+	reversed := slices.Clone(route)
+	slices.Reverse(reversed)
+
+	tests := []struct {
+		a, b    Point
+		agility int
+		want    []Point
+	}{
+		{Point{1, 0}, Point{2, 2}, 10, route},
+		{Point{2, 2}, Point{1, 0}, 10, reversed},
+		{Point{1, 0}, Point{2, 2}, 9, nil},
+		{Point{2, 2}, Point{1, 0}, 9, nil},
+		{Point{1, 0}, Point{1, 2}, 99, nil},
+		{Point{1, 0}, Point{1, 0}, 99, nil},
+	}
+	for _, tt := range tests {
+		s, got := m.ShortcutFrom(tt.a, tt.b, tt.agility)
+		if (s == nil) != (tt.want == nil) || !slices.Equal(got, tt.want) {
+			t.Errorf("ShortcutFrom(%v, %v, %d) = %v, %v; want %v", tt.a, tt.b, tt.agility, s, got, tt.want)
+		}
+	}
+
+	// Crossing back the other way mustn't turn the stored route round.
+	if !slices.Equal(m.Shortcuts[0].Route, route) {
+		t.Fatalf("route is now %v", m.Shortcuts[0].Route)
+	}
+}
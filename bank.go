@@ -0,0 +1,241 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+const (
+	// BankSize is how many different items the bank can hold.
+	BankSize = 400
+	// BankTabs counts the main tab, 0, which also shows every other tab.
+	BankTabs = 9
+)
+
+// bankSaveFile is where the bank is kept between sessions.
+const bankSaveFile = "bank.json"
+
+// BankSlot is one item's stack in the bank. Everything stacks in the bank,
+// so an item only ever has one slot. A slot with no items left in it is a
+// placeholder, keeping the item's place for when it is deposited again.
+type BankSlot struct {
+	ID    ItemID `json:"id"`
+	Count int    `json:"count"`
+	Tab   int    `json:"tab"`
+}
+
+func (s BankSlot) Placeholder() bool {
+	return s.ID != "" && s.Count == 0
+}
+
+type Bank struct {
+	Slots []BankSlot `json:"slots"`
+	// Placeholders leaves a placeholder behind when a slot is emptied.
+	Placeholders bool `json:"placeholders"`
+}
+
+func NewBank() *Bank {
+	return &Bank{Placeholders: true}
+}
+
+func (b *Bank) find(id ItemID) int {
+	return slices.IndexFunc(b.Slots, func(s BankSlot) bool { return s.ID == id })
+}
+
+// CanDeposit reports whether there is a slot for id, either its own or a
+// free one.
+func (b *Bank) CanDeposit(id ItemID) bool {
+	return b.find(id) >= 0 || len(b.Slots) < BankSize
+}
+
+// Deposit adds count of id to its slot, or to a new slot at the end of tab.
+// It returns how many didn't fit, which is all or nothing.
+func (b *Bank) Deposit(id ItemID, count, tab int) int {
+	if id == "" || count <= 0 {
+		return 0
+	}
+	if i := b.find(id); i >= 0 {
+		b.Slots[i].Count += count
+		return 0
+	}
+	if len(b.Slots) >= BankSize {
+		return count
+	}
+	b.Slots = append(b.Slots, BankSlot{ID: id, Count: count, Tab: tab})
+	return 0
+}
+
+// Withdraw takes up to count of id out of the bank and returns how many it
+// took.
+func (b *Bank) Withdraw(id ItemID, count int) int {
+	i := b.find(id)
+	if i < 0 {
+		return 0
+	}
+	n := min(count, b.Slots[i].Count)
+	b.Slots[i].Count -= n
+	if b.Slots[i].Count == 0 && !b.Placeholders {
+		b.Slots = slices.Delete(b.Slots, i, i+1)
+	}
+	return n
+}
+
+// Count is how many of id are in the bank.
+func (b *Bank) Count(id ItemID) int {
+	if i := b.find(id); i >= 0 {
+		return b.Slots[i].Count
+	}
+	return 0
+}
+
+func (b *Bank) HasItems(requirements []ItemSlot) bool {
+	for _, req := range requirements {
+		if b.Count(req.ID) < req.Count {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *Bank) ConsumeItems(requirements []ItemSlot) {
+	for _, req := range requirements {
+		b.Withdraw(req.ID, req.Count)
+	}
+}
+
+// Swap exchanges two slots. Each item takes over the other's tab, so the
+// tabs keep their shape.
+func (b *Bank) Swap(i, j int) {
+	s := b.Slots
+	s[i], s[j] = s[j], s[i]
+	s[i].Tab, s[j].Tab = s[j].Tab, s[i].Tab
+}
+
+// MoveToTab moves slot i to the end of tab.
+func (b *Bank) MoveToTab(i, tab int) {
+	s := b.Slots[i]
+	s.Tab = tab
+	b.Slots = append(slices.Delete(b.Slots, i, i+1), s)
+}
+
+// ReleasePlaceholder frees slot i if it is a placeholder.
+func (b *Bank) ReleasePlaceholder(i int) {
+	if b.Slots[i].Placeholder() {
+		b.Slots = slices.Delete(b.Slots, i, i+1)
+	}
+}
+
+// View lists the slots shown on tab whose item names contain search, in
+// display order. The main tab shows every tab, in tab order.
+func (b *Bank) View(tab int, search string) []int {
+	search = strings.ToLower(strings.TrimSpace(search))
+	var out []int
+	for i, s := range b.Slots {
+		if tab != 0 && s.Tab != tab {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(Item(s.ID).Name), search) {
+			continue
+		}
+		out = append(out, i)
+	}
+	if tab == 0 {
+		slices.SortStableFunc(out, func(i, j int) int { return b.Slots[i].Tab - b.Slots[j].Tab })
+	}
+	return out
+}
+
+// SaveBank writes the bank to bankSaveFile.
+func SaveBank(b *Bank) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(bankSaveFile, data, 0o644)
+}
+
+// LoadBank reads the bank back from bankSaveFile. A missing save file is not
+// an error.
+func LoadBank(b *Bank) error {
+	data, err := os.ReadFile(bankSaveFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, b)
+}
+
+// Deposit moves count of the item in inventory slot index into the bank,
+// taking from every slot that holds it. New items go on tab.
+func (p *Player) Deposit(index, count, tab int) {
+	item := p.Inventory.Get(index)
+	if item.Empty() {
+		return
+	}
+	have := p.Inventory.Count(item.ID)
+	if count == MakeAll || count > have {
+		count = have
+	}
+	if !p.Bank.CanDeposit(item.ID) {
+		showMessage("Your bank is full.")
+		return
+	}
+	p.Bank.Deposit(item.ID, count, tab)
+	p.Inventory.ConsumeItems([]ItemSlot{{ID: item.ID, Count: count}})
+}
+
+// DepositAll empties the inventory into the bank.
+func (p *Player) DepositAll(tab int) {
+	for i := range p.Inventory.Slots() {
+		p.Deposit(i, MakeAll, tab)
+	}
+}
+
+// DepositWorn moves whatever is worn in slot into the bank.
+func (p *Player) DepositWorn(slot EquipmentSlot, tab int) {
+	item := p.Equipment.Slots[slot]
+	if item.Empty() {
+		return
+	}
+	if !p.Bank.CanDeposit(item.ID) {
+		showMessage("Your bank is full.")
+		return
+	}
+	p.Bank.Deposit(item.ID, item.Count, tab)
+	p.Equipment.Unequip(slot)
+}
+
+// Withdraw takes up to count from bank slot index into the inventory, as
+// many as there is room for.
+func (p *Player) Withdraw(index, count int) {
+	s := p.Bank.Slots[index]
+	if s.Count == 0 {
+		return
+	}
+	if count == MakeAll || count > s.Count {
+		count = s.Count
+	}
+
+	room := count
+	switch {
+	case !Item(s.ID).Stackable:
+		room = min(count, p.Inventory.FreeSlots())
+	case !p.Inventory.CanAdd(ItemSlot{ID: s.ID, Count: count}):
+		room = 0
+	}
+	if room == 0 {
+		showMessage("You don't have enough inventory space.")
+		return
+	}
+	if room < count {
+		showMessage(fmt.Sprintf("You only have room for %d.", room))
+	}
+
+	n := p.Bank.Withdraw(s.ID, room)
+	p.Inventory.Add(ItemSlot{ID: s.ID, Count: n})
+}
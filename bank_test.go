@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func loadItems(t *testing.T) {
+	t.Helper()
+	if err := LoadItemDefs("assets/items.json"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBankDepositWithdraw(t *testing.T) {
+	b := NewBank()
+	if left := b.Deposit("logs", 5, 0); left != 0 {
+		t.Fatalf("Deposit left %d behind", left)
+	}
+	b.Deposit("logs", 3, 0)
+	if got := b.Count("logs"); got != 8 {
+		t.Fatalf("Count = %d, want 8", got)
+	}
+	if len(b.Slots) != 1 {
+		t.Fatalf("logs take %d slots, want 1", len(b.Slots))
+	}
+
+	if got := b.Withdraw("logs", 3); got != 3 {
+		t.Fatalf("Withdraw(3) = %d", got)
+	}
+	if got := b.Withdraw("logs", 10); got != 5 {
+		t.Fatalf("Withdraw(10) with 5 left = %d", got)
+	}
+	if got := b.Withdraw("oak_logs", 1); got != 0 {
+		t.Fatalf("Withdraw of an item never banked = %d", got)
+	}
+	if got := b.Count("logs"); got != 0 {
+		t.Fatalf("Count after withdrawing everything = %d", got)
+	}
+}
+
+func TestBankPlaceholders(t *testing.T) {
+	b := NewBank()
+	b.Deposit("logs", 5, 2)
+	b.Deposit("coal", 1, 0)
+
+	b.Withdraw("logs", 5)
+	if len(b.Slots) != 2 || !b.Slots[0].Placeholder() {
+		t.Fatalf("emptied slot wasn't kept as a placeholder: %v", b.Slots)
+	}
+	if b.Slots[0].Tab != 2 {
+		t.Fatalf("placeholder moved to tab %d", b.Slots[0].Tab)
+	}
+
+	// Depositing again refills the placeholder in place, on its own tab.
+	b.Deposit("logs", 4, 0)
+	if want := (BankSlot{ID: "logs", Count: 4, Tab: 2}); b.Slots[0] != want {
+		t.Fatalf("refilled slot = %v, want %v", b.Slots[0], want)
+	}
+
+	b.Placeholders = false
+	b.Withdraw("logs", 4)
+	if len(b.Slots) != 1 || b.Slots[0].ID != "coal" {
+		t.Fatalf("emptied slot kept with placeholders off: %v", b.Slots)
+	}
+}
+
+func TestBankReleasePlaceholder(t *testing.T) {
+	b := NewBank()
+	b.Deposit("logs", 5, 0)
+	b.Deposit("coal", 1, 0)
+
+	b.ReleasePlaceholder(0)
+	if len(b.Slots) != 2 {
+		t.Fatalf("released a slot that still holds items: %v", b.Slots)
+	}
+
+	b.Withdraw("logs", 5)
+	b.ReleasePlaceholder(0)
+	if len(b.Slots) != 1 || b.Slots[0].ID != "coal" {
+		t.Fatalf("placeholder not released: %v", b.Slots)
+	}
+}
+
+func TestBankFull(t *testing.T) {
+	b := NewBank()
+	for i := range BankSize {
+		b.Deposit(ItemID(fmt.Sprint("item", i)), 1, 0)
+	}
+	// A placeholder still takes up its slot.
+	b.Withdraw("item0", 1)
+
+	if b.CanDeposit("logs") {
+		t.Fatal("CanDeposit says a full bank has room for a new item")
+	}
+	if left := b.Deposit("logs", 7, 0); left != 7 {
+		t.Fatalf("Deposit into a full bank left %d behind, want all 7", left)
+	}
+	if b.Count("logs") != 0 || len(b.Slots) != BankSize {
+		t.Fatalf("full bank changed: %d slots, %d logs", len(b.Slots), b.Count("logs"))
+	}
+
+	if !b.CanDeposit("item0") {
+		t.Fatal("CanDeposit refuses an item with a placeholder")
+	}
+	if left := b.Deposit("item1", 2, 0); left != 0 || b.Count("item1") != 3 {
+		t.Fatalf("Deposit onto an existing stack left %d, count %d", left, b.Count("item1"))
+	}
+}
+
+func TestBankMatchesInventory(t *testing.T) {
+	loadItems(t)
+	held := []ItemSlot{{ID: "logs", Count: 3}, {ID: "coins", Count: 250}, {ID: "coal", Count: 2}}
+
+	requirements := [][]ItemSlot{
+		{{ID: "logs", Count: 3}},
+		{{ID: "logs", Count: 4}},
+		{{ID: "coins", Count: 100}, {ID: "coal", Count: 2}},
+		{{ID: "coins", Count: 100}, {ID: "coal", Count: 3}},
+		{{ID: "iron_ore", Count: 1}},
+		{},
+	}
+	for _, req := range requirements {
+		inv, b := &Inventory{}, NewBank()
+		for _, s := range held {
+			inv.Add(s)
+			b.Deposit(s.ID, s.Count, 0)
+		}
+
+		has := inv.HasItems(req)
+		if got := b.HasItems(req); got != has {
+			t.Fatalf("HasItems(%v): bank says %v, inventory %v", req, got, has)
+		}
+		if !has {
+			continue
+		}
+		inv.ConsumeItems(req)
+		b.ConsumeItems(req)
+		for _, s := range held {
+			if got, want := b.Count(s.ID), inv.Count(s.ID); got != want {
+				t.Fatalf("after ConsumeItems(%v) bank has %d %s, inventory %d", req, got, s.ID, want)
+			}
+		}
+	}
+}
+
+func TestBankView(t *testing.T) {
+	loadItems(t)
+	b := NewBank()
+	b.Deposit("iron_ore", 1, 3)
+	b.Deposit("logs", 1, 0)
+	b.Deposit("oak_logs", 1, 3)
+	b.Deposit("coal", 1, 1)
+
+	ids := func(view []int) []ItemID {
+		var out []ItemID
+		for _, i := range view {
+			out = append(out, b.Slots[i].ID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		tab    int
+		search string
+		want   []ItemID
+	}{
+		{0, "", []ItemID{"logs", "coal", "iron_ore", "oak_logs"}},
+		{3, "", []ItemID{"iron_ore", "oak_logs"}},
+		{2, "", nil},
+		{0, "LOGS", []ItemID{"logs", "oak_logs"}},
+		{3, " logs ", []ItemID{"oak_logs"}},
+		{0, "rune", nil},
+	}
+	for _, tt := range tests {
+		if got := ids(b.View(tt.tab, tt.search)); !slices.Equal(got, tt.want) {
+			t.Errorf("View(%d, %q) = %v, want %v", tt.tab, tt.search, got, tt.want)
+		}
+	}
+}
+
+func TestBankSaveLoad(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	b := NewBank()
+	if err := LoadBank(b); err != nil {
+		t.Fatalf("LoadBank with no save file: %v", err)
+	}
+
+	b.Deposit("logs", 5, 2)
+	b.Deposit("coins", 1000, 0)
+	b.Withdraw("logs", 5)
+	b.Placeholders = false
+	if err := SaveBank(b); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(bankSaveFile); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := NewBank()
+	if err := LoadBank(loaded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, b) {
+		t.Fatalf("loaded %+v, saved %+v", loaded, b)
+	}
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// The bank panel sits under the equipment panel while the player stands at
+// a booth. Tabs and the deposit-all and close buttons run along the top, the
+// search box, quantity picker and placeholder toggle below them, and a
+// scrolling grid of slots fills the rest. Withdrawals use the same quantity
+// picker as crafting.
+var (
+	bankOpen   bool
+	bankPos    Point
+	bankTab    int
+	bankSearch string
+	searching  bool
+	bankScroll int // rows scrolled down
+)
+
+const (
+	bankX       = 330
+	bankY       = 324
+	bankColumns = 10
+	bankRows    = 4
+	bankRowH    = 24
+	bankTabW    = 30
+	bankSearchW = 130
+	bankWidth   = bankColumns * (slotSize + slotPadding)
+)
+
+// OpenBank walks the player up to a bank booth and opens the bank.
+func (p *Player) OpenBank(tileX, tileY int) {
+	pos := Point{tileX, tileY}
+	p.Actions.Replace(
+		NewWalkAdjacentAction(p, pos),
+		NewInteractAction(p, pos, "", func(p *Player, target Point) bool {
+			tile := p.Map.GetTile(target.X, target.Y)
+			if tile == nil || !tile.IsBank() {
+				return false
+			}
+			closeStation()
+			bankOpen = true
+			bankPos = target
+			showInventory = true
+			return true
+		}),
+	)
+}
+
+func closeBank() {
+	bankOpen = false
+	searching = false
+}
+
+// bankView is the bank slots on show, in order.
+func bankView() []int {
+	return player.Bank.View(bankTab, bankSearch)
+}
+
+func bankTabRect(tab int) rl.Rectangle {
+	return rl.NewRectangle(float32(bankX+tab*(bankTabW+2)), bankY, bankTabW, bankRowH)
+}
+
+func bankCloseRect() rl.Rectangle {
+	return rl.NewRectangle(bankX+bankWidth-bankRowH, bankY, bankRowH, bankRowH)
+}
+
+func depositAllRect() rl.Rectangle {
+	return rl.NewRectangle(bankX+bankWidth-bankRowH-94, bankY, 90, bankRowH)
+}
+
+func bankSearchRect() rl.Rectangle {
+	return rl.NewRectangle(bankX, bankY+bankRowH+4, bankSearchW, bankRowH)
+}
+
+// bankQuantityX is where the quantity picker starts on the second row.
+const bankQuantityX = bankX + bankSearchW + 6
+
+func placeholderRect() rl.Rectangle {
+	x := xRect(bankQuantityX, 0).X + quantityButtonW + 6
+	return rl.NewRectangle(x, bankY+bankRowH+4, bankX+bankWidth-x, bankRowH)
+}
+
+// bankSlotRect is where the i'th slot of the view is drawn, and whether it
+// is scrolled into sight.
+func bankSlotRect(i int) (rl.Rectangle, bool) {
+	i -= bankScroll * bankColumns
+	top := bankY + 2*(bankRowH+4)
+	return gridSlotRect(bankX, top, i, bankColumns), i >= 0 && i < bankColumns*bankRows
+}
+
+func bankPanelRect() rl.Rectangle {
+	return rl.NewRectangle(bankX, bankY, bankWidth, 2*(bankRowH+4)+bankRows*(slotSize+slotPadding))
+}
+
+// updateBankSearch takes typing into the search box while it has focus.
+func updateBankSearch() {
+	if !searching {
+		return
+	}
+	for c := rl.GetCharPressed(); c > 0; c = rl.GetCharPressed() {
+		if c >= 32 && c < 127 && len(bankSearch) < 20 {
+			bankSearch += string(c)
+			bankScroll = 0
+		}
+	}
+	if rl.IsKeyPressed(rl.KeyBackspace) && len(bankSearch) > 0 {
+		bankSearch = bankSearch[:len(bankSearch)-1]
+	}
+	if rl.IsKeyPressed(rl.KeyEnter) || IsInputPressed(InputCancel) {
+		searching = false
+	}
+}
+
+// typing reports whether a text box has the keyboard, so keys type rather
+// than move or toggle things.
+func typing() bool {
+	return searching || enteringX
+}
+
+// CheckBankClick handles clicks on the bank's buttons and tabs; dragging and
+// clicking slots goes through the panel layer. It reports whether the mouse
+// is over the bank so the click doesn't also walk.
+func (p *Player) CheckBankClick() bool {
+	if !bankOpen || !showInventory {
+		return false
+	}
+	if !adjacent(p.CurrentTile(), bankPos) {
+		closeBank()
+		return false
+	}
+
+	mouse := rl.GetMousePosition()
+	if !rl.CheckCollisionPointRec(mouse, bankPanelRect()) {
+		if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+			searching = false
+		}
+		return false
+	}
+
+	if wheel := rl.GetMouseWheelMove(); wheel != 0 {
+		rows := (len(bankView()) + bankColumns - 1) / bankColumns
+		bankScroll = min(max(bankScroll-int(wheel), 0), max(rows-bankRows, 0))
+	}
+
+	if checkQuantityClick(bankQuantityX, int(bankSearchRect().Y)) {
+		searching = false
+	}
+	if !rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		return true
+	}
+
+	searching = rl.CheckCollisionPointRec(mouse, bankSearchRect())
+	if searching {
+		enteringX = false
+	}
+	for tab := range BankTabs {
+		if rl.CheckCollisionPointRec(mouse, bankTabRect(tab)) {
+			bankTab = tab
+			bankScroll = 0
+		}
+	}
+	switch {
+	case rl.CheckCollisionPointRec(mouse, bankCloseRect()):
+		closeBank()
+	case rl.CheckCollisionPointRec(mouse, depositAllRect()):
+		p.DepositAll(bankTab)
+	case rl.CheckCollisionPointRec(mouse, placeholderRect()):
+		p.Bank.Placeholders = !p.Bank.Placeholders
+	}
+	return true
+}
+
+// bankOptions lists what can be done with bank slot index.
+func bankOptions(index int) []MenuOption {
+	s := player.Bank.Slots[index]
+	name := Item(s.ID).Name
+	if s.Placeholder() {
+		return []MenuOption{{"Release", name + " placeholder", func() { player.Bank.ReleasePlaceholder(index) }}}
+	}
+
+	opts := quantityMenu("Withdraw", name, func(n int) { player.Withdraw(index, n) })
+	if examine := Item(s.ID).Examine; examine != "" {
+		opts = append(opts, examineOption(name, examine))
+	}
+	return opts
+}
+
+// depositOptions replaces an inventory item's usual options while the bank
+// is open.
+func depositOptions(index int) []MenuOption {
+	def := player.Inventory.Get(index).Def()
+	opts := quantityMenu("Deposit", def.Name, func(n int) { player.Deposit(index, n, bankTab) })
+	if def.Examine != "" {
+		opts = append(opts, examineOption(def.Name, def.Examine))
+	}
+	return opts
+}
+
+// quantityMenu is verb-1, verb-5 and so on, with the quantity picked in the
+// picker first so that it is what a left-click does.
+func quantityMenu(verb, target string, do func(n int)) []MenuOption {
+	counts := []int{makeQuantity}
+	for _, q := range quantityOptions {
+		if q.Count != makeQuantity {
+			counts = append(counts, q.Count)
+		}
+	}
+
+	var opts []MenuOption
+	for _, n := range counts {
+		label := "All"
+		if n != MakeAll {
+			label = strconv.Itoa(n)
+		}
+		opts = append(opts, MenuOption{verb + "-" + label, target, func() { do(n) }})
+	}
+	return opts
+}
+
+func (p *Player) DrawBank() {
+	if !bankOpen {
+		return
+	}
+	panel := bankPanelRect()
+	rl.DrawRectangleRec(panel, rl.Fade(rl.RayWhite, 0.95))
+	rl.DrawRectangleLinesEx(panel, 1, rl.DarkGray)
+
+	for tab := range BankTabs {
+		label := strconv.Itoa(tab)
+		if tab == 0 {
+			label = "All"
+		}
+		drawQuantityButton(bankTabRect(tab), label, tab == bankTab)
+	}
+	drawQuantityButton(depositAllRect(), "Deposit all", false)
+	drawQuantityButton(bankCloseRect(), "X", false)
+
+	search := bankSearch
+	if searching {
+		search += "_"
+	} else if search == "" {
+		search = "Search"
+	}
+	drawQuantityButton(bankSearchRect(), search, searching)
+	drawQuantityPicker(bankQuantityX, int(bankSearchRect().Y))
+	drawQuantityButton(placeholderRect(), "Placeholders", p.Bank.Placeholders)
+
+	view := bankView()
+	for i, index := range view {
+		rect, visible := bankSlotRect(i)
+		if !visible {
+			continue
+		}
+		rl.DrawRectangleRec(rect, rl.LightGray)
+		rl.DrawRectangleLinesEx(rect, 1, rl.DarkGray)
+
+		s := p.Bank.Slots[index]
+		item := ItemSlot{ID: s.ID, Count: s.Count}
+		switch {
+		case s.Placeholder():
+			drawItem(item, rect, 0.3, false)
+		case isDragged(SlotRef{PanelBank, index}):
+			drawItem(item, rect, 0.3, true)
+		default:
+			drawItem(item, rect, 1, true)
+		}
+	}
+
+	footer := fmt.Sprintf("%d / %d", len(p.Bank.Slots), BankSize)
+	rl.DrawText(footer, int32(panel.X+panel.Width)-rl.MeasureText(footer, 10)-4, int32(panel.Y+panel.Height)-12, 10, rl.DarkGray)
+}
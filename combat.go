@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	// combatRange is how close, in pixels, the player must be to engage an
+	// enemy.
+	combatRange = float32(TileSize * 2)
+	// xpPerDamage is combat experience per point of damage dealt, with a
+	// third as much going to Hitpoints on top.
+	xpPerDamage          = 4.0
+	hitpointsXPPerDamage = 4.0 / 3
+)
+
+// rangedBow returns the bow the player is wielding, or nil when fighting in
+// melee.
+func (p *Player) rangedBow() *Bow {
+	return bowByItem(p.Equipment.Slots[SlotWeapon].ID)
+}
+
+// attackRange is how close, in pixels, the player must be to keep fighting.
+func (p *Player) attackRange() float32 {
+	if bow := p.rangedBow(); bow != nil {
+		return float32(bow.Range * TileSize)
+	}
+	return combatRange
+}
+
+// fireArrow takes one arrow from the ammo slot and rolls how hard it hits
+// from bow, anywhere from 0 up to the arrow's damage plus the bow's bonus. ok
+// is false when the quiver is empty.
+func (p *Player) fireArrow(bow *Bow) (damage int, ok bool) {
+	arrows := p.Equipment.Slots[SlotAmmo]
+	if !isArrow(arrows.ID) || arrows.Count <= 0 {
+		return 0, false
+	}
+	damage = sim.Rand.Intn(arrowDamage[arrows.ID] + bow.Bonus + 1)
+
+	arrows.Count--
+	if arrows.Count <= 0 {
+		arrows = ItemSlot{}
+	}
+	p.Equipment.Slots[SlotAmmo] = arrows
+	return damage, true
+}
+
+func startCombat(e *Enemy) {
+	inCombat = true
+	currentEnemy = e
+	playerTurn = true
+	combatTimer = combatInterval
+}
+
+// updateCombat starts fights with enemies the player bumps into and plays
+// out turns, one every combatInterval game ticks.
+func updateCombat(t Tick) {
+	if !inCombat {
+		for i := range enemies {
+			if rl.Vector2Distance(player.Pos, enemies[i].Pos) < float32(TileSize) {
+				startCombat(&enemies[i])
+				break
+			}
+		}
+	}
+
+	if inCombat && currentEnemy != nil && t.Game {
+		combatTimer--
+		if combatTimer <= 0 {
+			if playerTurn {
+				damage, skill := 10, SkillAttack
+				if bow := player.rangedBow(); bow != nil {
+					arrowDamage, ok := player.fireArrow(bow)
+					if !ok {
+						showMessage("There is no ammo left in your quiver.")
+						inCombat = false
+						currentEnemy = nil
+						return
+					}
+					damage, skill = arrowDamage, SkillRanged
+				}
+
+				damage = min(damage, currentEnemy.Health)
+				currentEnemy.Health -= damage
+				fmt.Println("Player hits", currentEnemy.Name, "for", damage, "damage")
+				player.GainXP(skill, xpPerDamage*float64(damage))
+				player.GainXP(SkillHitpoints, hitpointsXPPerDamage*float64(damage))
+
+				if currentEnemy.Health <= 0 {
+					fmt.Println(currentEnemy.Name, "is defeated!")
+
+					for _, loot := range currentEnemy.LootTable {
+						if sim.Rand.Float32() <= loot.Chance {
+							DropGroundItem(currentEnemy.Tile(), loot.Item, &player)
+						}
+					}
+
+					removeDeadEnemies()
+
+					inCombat = false
+					currentEnemy = nil
+					return
+				}
+			} else {
+				player.Health -= 5
+				fmt.Println(currentEnemy.Name, "hits Player for 5 damage")
+
+				if player.Health <= 0 {
+					fmt.Println("You died!")
+					inCombat = false
+					currentEnemy = nil
+					return
+				}
+			}
+			playerTurn = !playerTurn
+			combatTimer = combatInterval
+		}
+	}
+
+	if inCombat && currentEnemy != nil && rl.Vector2Distance(player.Pos, currentEnemy.Pos) > player.attackRange() {
+		fmt.Println("You escaped combat.")
+		inCombat = false
+		currentEnemy = nil
+	}
+}
+
+// removeDeadEnemies compacts enemies in place, which moves the survivors;
+// see enemyByID.
+func removeDeadEnemies() {
+	alive := enemies[:0]
+	for _, e := range enemies {
+		if e.Health > 0 {
+			alive = append(alive, e)
+		}
+	}
+	enemies = alive
+}
+
+// enemyAt returns the enemy standing on tile, or nil.
+func enemyAt(tile Point) *Enemy {
+	for i := range enemies {
+		if enemies[i].Tile() == tile {
+			return &enemies[i]
+		}
+	}
+	return nil
+}
+
+// TryAttack replaces whatever the player is doing with walking up to the
+// enemy and fighting it. With a bow the player only walks until they have a
+// clear shot.
+func (p *Player) TryAttack(e *Enemy) {
+	if bow := p.rangedBow(); bow != nil {
+		p.Actions.Replace(NewWalkInRangeAction(p, e.Tile(), bow.Range), NewAttackAction(p, e))
+		return
+	}
+	p.Actions.Replace(NewWalkAction(p, e.Tile()), NewAttackAction(p, e))
+}
+
+// AttackAction engages an enemy and runs until the fight is over. It fails
+// if the enemy is gone by the time the player gets there.
+type AttackAction struct {
+	p        *Player
+	TargetID int
+}
+
+func NewAttackAction(p *Player, target *Enemy) *AttackAction {
+	return &AttackAction{p: p, TargetID: target.ID}
+}
+
+// fighting reports whether the player is in combat with the target.
+func (a *AttackAction) fighting() bool {
+	return inCombat && currentEnemy != nil && currentEnemy.ID == a.TargetID
+}
+
+func (a *AttackAction) Start() bool {
+	if a.fighting() {
+		return true
+	}
+	target := enemyByID(a.TargetID)
+	if target == nil {
+		return false
+	}
+	if bow := a.p.rangedBow(); bow != nil {
+		if a.p.Skills.Level(SkillRanged) < bow.Level {
+			showMessage(fmt.Sprintf("You need a Ranged level of %d to use that bow.", bow.Level))
+			return false
+		}
+		if !a.p.Map.LineOfSight(a.p.CurrentTile(), target.Tile()) {
+			fmt.Println("Can't see", target.Name)
+			return false
+		}
+	}
+	if rl.Vector2Distance(a.p.Pos, target.Pos) > a.p.attackRange() {
+		fmt.Println("Can't reach", target.Name)
+		return false
+	}
+	startCombat(target)
+	return true
+}
+
+func (a *AttackAction) Update(t Tick) ActionStatus {
+	if a.fighting() {
+		return ActionRunning
+	}
+	return ActionDone
+}
+
+// Cancel ends the fight, so walking away or starting something else stops
+// the attacks.
+func (a *AttackAction) Cancel() {
+	if a.fighting() {
+		inCombat = false
+		currentEnemy = nil
+	}
+}
+
+func (a *AttackAction) Complete() {}
+
+func (a *AttackAction) Label() string { return "" }
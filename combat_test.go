@@ -0,0 +1,82 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// tilePos is the top-left pixel of a tile, where entities on it stand.
+func tilePos(x, y int) rl.Vector2 {
+	return rl.NewVector2(float32(x*TileSize), float32(y*TileSize))
+}
+
+func TestAttackActionSurvivesCompaction(t *testing.T) {
+	gameMap = NewMap(10, 10)
+	player = NewPlayer(5*TileSize, 4*TileSize, gameMap, rl.Texture2D{}, rl.Texture2D{})
+	inCombat, currentEnemy = false, nil
+	enemies = nil
+	spawnEnemy(Enemy{Name: "First", Pos: tilePos(1, 1), Health: 10})
+	spawnEnemy(Enemy{Name: "Second", Pos: tilePos(5, 5), Health: 10})
+	second := enemies[1].ID
+
+	attack := NewAttackAction(&player, &enemies[1])
+
+	// The first enemy dies, and the second slides down into its place.
+	enemies[0].Health = 0
+	removeDeadEnemies()
+
+	if !attack.Start() {
+		t.Fatal("Start failed after another enemy died")
+	}
+	if currentEnemy != &enemies[0] || currentEnemy.ID != second {
+		t.Fatalf("fighting %+v, want the second enemy, now at enemies[0]", currentEnemy)
+	}
+	if attack.Update(Tick{}) != ActionRunning {
+		t.Fatal("Update stopped while the fight is on")
+	}
+
+	attack.Cancel()
+	if inCombat || currentEnemy != nil {
+		t.Fatal("Cancel didn't end the fight")
+	}
+
+	enemies[0].Health = 0
+	removeDeadEnemies()
+	if (&AttackAction{p: &player, TargetID: second}).Start() {
+		t.Fatal("Start succeeded against an enemy that is gone")
+	}
+}
+
+func TestFireArrowRollsDamage(t *testing.T) {
+	sim = NewSim(1, time.Now)
+	player = NewPlayer(0, 0, NewMap(5, 5), rl.Texture2D{}, rl.Texture2D{})
+	player.Equipment.Slots[SlotAmmo] = ItemSlot{ID: "iron_arrow", Count: 500}
+	bow := &Bow{Item: "longbow", Bonus: 1}
+
+	seen := map[int]bool{}
+	for range 500 {
+		damage, ok := player.fireArrow(bow)
+		if !ok {
+			t.Fatal("ran out of arrows early")
+		}
+		seen[damage] = true
+	}
+	// Iron arrows hit up to 9, plus 1 from the longbow.
+	for d := 0; d <= 10; d++ {
+		if !seen[d] {
+			t.Errorf("never hit for %d", d)
+		}
+	}
+	if len(seen) != 11 {
+		t.Errorf("hits %v, want 0 to 10", seen)
+	}
+
+	if !player.Equipment.Slots[SlotAmmo].Empty() {
+		t.Fatalf("quiver holds %+v after firing every arrow", player.Equipment.Slots[SlotAmmo])
+	}
+	if _, ok := player.fireArrow(bow); ok {
+		t.Fatal("fired from an empty quiver")
+	}
+}
@@ -17,4 +17,11 @@ const (
 	TileTree
 	TileWater
 	TileRock
+	TileRange
+	TileFire
+	TileFurnace
+	TileAnvil
+	TileFarmPatch
+	TileWall
+	TileBankBooth
 )
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+)
+
+const (
+	// cookTicks is how many game ticks cooking one item takes.
+	cookTicks = 4
+	// baseBurnChance is the chance of burning food at exactly the level
+	// needed to cook it. It falls off linearly to nothing at StopBurn.
+	baseBurnChance = float32(0.6)
+	// rangeBurnBonus is how many levels a range is worth over an open fire.
+	rangeBurnBonus = 3
+)
+
+// Cookable links the raw, cooked and burnt versions of a food.
+type Cookable struct {
+	Raw      ItemID
+	Cooked   ItemID
+	Burnt    ItemID
+	Level    int
+	XP       float64
+	StopBurn int // level from which it never burns
+	Heals    int // health restored by eating the cooked version
+}
+
+var cookables = []Cookable{
+	{"raw_shrimps", "shrimps", "burnt_shrimps", 1, 30, 34, 30},
+	{"raw_sardine", "sardine", "burnt_fish", 1, 40, 38, 40},
+	{"raw_trout", "trout", "burnt_fish", 15, 70, 49, 70},
+	{"raw_salmon", "salmon", "burnt_fish", 25, 90, 58, 90},
+}
+
+func cookableByRaw(id ItemID) *Cookable {
+	for i := range cookables {
+		if cookables[i].Raw == id {
+			return &cookables[i]
+		}
+	}
+	return nil
+}
+
+// foodHeals returns how much health eating the item restores, or 0 if it
+// isn't food.
+func foodHeals(id ItemID) int {
+	for _, c := range cookables {
+		if c.Cooked == id {
+			return c.Heals
+		}
+	}
+	return 0
+}
+
+// BurnChance is the chance of burning c at the given Cooking level. Ranges
+// burn less than open fires.
+func (c Cookable) BurnChance(level int, onRange bool) float32 {
+	if onRange {
+		level += rangeBurnBonus
+	}
+	if level >= c.StopBurn {
+		return 0
+	}
+	return baseBurnChance * float32(c.StopBurn-level) / float32(c.StopBurn-c.Level)
+}
+
+// TryCookAt replaces whatever the player is doing with walking up to a fire
+// or range and cooking everything raw they are carrying.
+func (p *Player) TryCookAt(tileX, tileY int) {
+	target := Point{tileX, tileY}
+	p.Actions.Replace(NewWalkAdjacentAction(p, target), NewCookAction(p, target))
+}
+
+// CookAction cooks raw food on a fire or range one item at a time until the
+// player runs out of food they can cook or the fire goes out.
+type CookAction struct {
+	p      *Player
+	Source Point
+	ticks  int
+}
+
+func NewCookAction(p *Player, source Point) *CookAction {
+	return &CookAction{p: p, Source: source}
+}
+
+// nextRaw returns the first raw food in the inventory the player has the
+// level to cook, plus the first one they don't, for the error message.
+func (c *CookAction) nextRaw() (cookable, tooHigh *Cookable) {
+	level := c.p.Skills.Level(SkillCooking)
+	for _, slot := range c.p.Inventory.Slots() {
+		food := cookableByRaw(slot.ID)
+		if food == nil {
+			continue
+		}
+		if level >= food.Level {
+			return food, nil
+		}
+		if tooHigh == nil {
+			tooHigh = food
+		}
+	}
+	return nil, tooHigh
+}
+
+// source returns the fire or range being cooked on, or nil once it has gone
+// (a fire burning out).
+func (c *CookAction) source() *Tile {
+	tile := c.p.Map.GetTile(c.Source.X, c.Source.Y)
+	if tile == nil || !tile.IsCookingSource() {
+		return nil
+	}
+	return tile
+}
+
+func (c *CookAction) Start() bool {
+	if c.source() == nil || !adjacent(c.p.CurrentTile(), c.Source) {
+		return false
+	}
+
+	food, tooHigh := c.nextRaw()
+	switch {
+	case food == nil && tooHigh != nil:
+		showMessage(fmt.Sprintf("You need a Cooking level of %d to cook %s.", tooHigh.Level, itemName(tooHigh.Cooked)))
+		return false
+	case food == nil:
+		showMessage("You have nothing to cook.")
+		return false
+	}
+
+	c.ticks = cookTicks
+	return true
+}
+
+func (c *CookAction) Update(t Tick) ActionStatus {
+	source := c.source()
+	if source == nil {
+		return ActionDone
+	}
+
+	if t.Game {
+		c.ticks--
+	}
+	if c.ticks > 0 {
+		return ActionRunning
+	}
+	c.ticks = cookTicks
+
+	food, _ := c.nextRaw()
+	if food == nil {
+		return ActionDone
+	}
+
+	c.p.Inventory.ConsumeItems([]ItemSlot{{ID: food.Raw, Count: 1}})
+	if sim.Rand.Float32() < food.BurnChance(c.p.Skills.Level(SkillCooking), source.Type == TileRange) {
+		c.p.Inventory.Add(ItemSlot{ID: food.Burnt, Count: 1})
+		showMessage("You accidentally burn the " + itemName(food.Cooked) + ".")
+	} else {
+		c.p.Inventory.Add(ItemSlot{ID: food.Cooked, Count: 1})
+		c.p.GainXP(SkillCooking, food.XP)
+	}
+
+	if next, _ := c.nextRaw(); next == nil {
+		return ActionDone
+	}
+	return ActionRunning
+}
+
+func (c *CookAction) Cancel() {}
+
+func (c *CookAction) Complete() {}
+
+func (c *CookAction) Label() string { return "Cooking..." }
+
+// Eat eats the food in the given inventory slot, if it is food.
+func (p *Player) Eat(index int) bool {
+	item := p.Inventory.Get(index)
+	heals := foodHeals(item.ID)
+	if heals == 0 {
+		return false
+	}
+	name := itemName(item.ID)
+
+	item.Count--
+	if item.Count <= 0 {
+		item = ItemSlot{}
+	}
+	p.Inventory.Set(index, item)
+
+	p.Health = min(p.Health+heals, p.MaxHealth)
+	showMessage("You eat the " + name + ".")
+	return true
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBurnChance(t *testing.T) {
+	tests := []struct {
+		raw     ItemID
+		level   int
+		onRange bool
+		want    float32
+	}{
+		{"raw_shrimps", 1, false, 0.6},
+		{"raw_shrimps", 1, true, 0.6 * 30 / 33},
+		{"raw_shrimps", 17, false, 0.6 * 17 / 33},
+		{"raw_shrimps", 33, false, 0.6 / 33},
+		{"raw_shrimps", 34, false, 0},
+		{"raw_shrimps", 31, true, 0},
+		{"raw_shrimps", 99, false, 0},
+		{"raw_trout", 15, false, 0.6},
+		{"raw_trout", 48, false, 0.6 / 34},
+		{"raw_salmon", 55, true, 0},
+	}
+	for _, tt := range tests {
+		c := cookableByRaw(tt.raw)
+		if got := c.BurnChance(tt.level, tt.onRange); math.Abs(float64(got-tt.want)) > 1e-6 {
+			t.Errorf("%s at level %d (range %v): %v, want %v", tt.raw, tt.level, tt.onRange, got, tt.want)
+		}
+	}
+}
+
+func TestFoodHeals(t *testing.T) {
+	tests := []struct {
+		id   ItemID
+		want int
+	}{
+		{"shrimps", 30},
+		{"salmon", 90},
+		{"raw_salmon", 0},
+		{"burnt_fish", 0},
+		{"logs", 0},
+	}
+	for _, tt := range tests {
+		if got := foodHeals(tt.id); got != tt.want {
+			t.Errorf("foodHeals(%s) = %d, want %d", tt.id, got, tt.want)
+		}
+	}
+}
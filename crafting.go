@@ -0,0 +1,348 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Station is a tile that some recipes have to be made next to.
+type Station int
+
+const (
+	StationNone Station = iota
+	StationFurnace
+	StationAnvil
+)
+
+var stationTiles = map[Station]int{
+	StationFurnace: TileFurnace,
+	StationAnvil:   TileAnvil,
+}
+
+var stationNames = map[Station]string{
+	StationFurnace: "Furnace",
+	StationAnvil:   "Anvil",
+}
+
+var stationLabels = map[Station]string{
+	StationNone:    "Crafting...",
+	StationFurnace: "Smelting...",
+	StationAnvil:   "Smithing...",
+}
+
+// stationAt returns the station a tile type acts as, if any.
+func stationAt(tileType int) Station {
+	for station, t := range stationTiles {
+		if t == tileType {
+			return station
+		}
+	}
+	return StationNone
+}
+
+// Recipes is every recipe in the game, from all skills.
+var Recipes = slices.Concat(smithingRecipes, fletchingRecipes)
+
+// MakeAll as a quantity means keep going until the materials run out.
+const MakeAll = -1
+
+// CanMake reports whether the player could make one of recipe right now,
+// ignoring where they stand. If not, problem says why.
+func (p *Player) CanMake(r Recipe) (ok bool, problem string) {
+	if r.Level > 0 && p.Skills.Level(r.Skill) < r.Level {
+		return false, fmt.Sprintf("You need a %s level of %d to make that.", r.Skill, r.Level)
+	}
+	for _, tool := range r.Tools {
+		if !p.Inventory.HasItems([]ItemSlot{{ID: tool, Count: 1}}) {
+			return false, fmt.Sprintf("You need %s %s to make that.", article(itemName(tool)), itemName(tool))
+		}
+	}
+	if !p.Inventory.HasItems(r.Inputs) {
+		return false, "You don't have the materials to make that."
+	}
+	return true, ""
+}
+
+// CraftAction makes Count of a recipe, one every Recipe.Ticks game ticks,
+// standing next to the station at StationPos if the recipe needs one.
+type CraftAction struct {
+	p          *Player
+	Recipe     Recipe
+	Count      int // how many to make, or MakeAll
+	StationPos Point
+	made       int
+	ticks      int
+}
+
+func NewCraftAction(p *Player, recipe Recipe, count int, stationPos Point) *CraftAction {
+	return &CraftAction{p: p, Recipe: recipe, Count: count, StationPos: stationPos}
+}
+
+func (c *CraftAction) atStation() bool {
+	if c.Recipe.Station == StationNone {
+		return true
+	}
+	tile := c.p.Map.GetTile(c.StationPos.X, c.StationPos.Y)
+	return tile != nil && stationAt(tile.Type) == c.Recipe.Station && adjacent(c.p.CurrentTile(), c.StationPos)
+}
+
+func (c *CraftAction) Start() bool {
+	if !c.atStation() {
+		showMessage(fmt.Sprintf("You need to be at %s %s to make that.", article(stationNames[c.Recipe.Station]), stationNames[c.Recipe.Station]))
+		return false
+	}
+	if ok, problem := c.p.CanMake(c.Recipe); !ok {
+		showMessage(problem)
+		return false
+	}
+	c.ticks = c.Recipe.Ticks
+	return true
+}
+
+func (c *CraftAction) Update(t Tick) ActionStatus {
+	if !c.atStation() {
+		return ActionDone
+	}
+
+	if t.Game {
+		c.ticks--
+	}
+	if c.ticks > 0 {
+		return ActionRunning
+	}
+	c.ticks = c.Recipe.Ticks
+
+	c.p.TryCraft(c.Recipe)
+	c.made++
+
+	if c.Count != MakeAll && c.made >= c.Count {
+		return ActionDone
+	}
+	if ok, _ := c.p.CanMake(c.Recipe); !ok {
+		return ActionDone
+	}
+	return ActionRunning
+}
+
+func (c *CraftAction) Cancel() {}
+
+func (c *CraftAction) Complete() {}
+
+func (c *CraftAction) Label() string { return stationLabels[c.Recipe.Station] }
+
+// The station panel lists what can be made at the furnace or anvil the
+// player last walked up to, with a make-X quantity picker along the top. It
+// takes the crafting panel's place in the top right while it is open, and
+// shares the same picker.
+var (
+	openStation    Station
+	openStationPos Point
+	makeQuantity   = 1
+	enteringX      bool
+	xInput         string
+)
+
+const (
+	stationPanelX   = ScreenWidth - stationRowWidth - 10
+	stationPanelY   = 10
+	stationRowWidth = 210
+	stationRowH     = 24
+	quantityButtonW = 34
+)
+
+type quantityOption struct {
+	Label string
+	Count int
+}
+
+var quantityOptions = []quantityOption{
+	{"1", 1}, {"5", 5}, {"10", 10}, {"All", MakeAll},
+}
+
+// OpenStation walks the player up to a furnace or anvil and opens its panel.
+func (p *Player) OpenStation(tileX, tileY int) {
+	pos := Point{tileX, tileY}
+	p.Actions.Replace(
+		NewWalkAdjacentAction(p, pos),
+		NewInteractAction(p, pos, "", func(p *Player, target Point) bool {
+			tile := p.Map.GetTile(target.X, target.Y)
+			if tile == nil || stationAt(tile.Type) == StationNone {
+				return false
+			}
+			openStation = stationAt(tile.Type)
+			openStationPos = target
+			return true
+		}),
+	)
+}
+
+func closeStation() {
+	openStation = StationNone
+	enteringX = false
+}
+
+func stationRecipes(station Station) []Recipe {
+	var out []Recipe
+	for _, r := range Recipes {
+		if r.Station == station {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func quantityRect(x, y, i int) rl.Rectangle {
+	return rl.NewRectangle(float32(x+i*(quantityButtonW+4)), float32(y), quantityButtonW, stationRowH)
+}
+
+// xRect is the make-X button after the fixed quantities.
+func xRect(x, y int) rl.Rectangle {
+	return quantityRect(x, y, len(quantityOptions))
+}
+
+// updateQuantityInput takes typing into the make-X box while it is open.
+func updateQuantityInput() {
+	if !enteringX {
+		return
+	}
+	for c := rl.GetCharPressed(); c > 0; c = rl.GetCharPressed() {
+		if c >= '0' && c <= '9' && len(xInput) < 3 {
+			xInput += string(c)
+		}
+	}
+	if rl.IsKeyPressed(rl.KeyBackspace) && len(xInput) > 0 {
+		xInput = xInput[:len(xInput)-1]
+	}
+	if rl.IsKeyPressed(rl.KeyEnter) {
+		if n, err := strconv.Atoi(xInput); err == nil && n > 0 {
+			makeQuantity = n
+		}
+		enteringX = false
+	}
+	if IsInputPressed(InputCancel) {
+		enteringX = false
+	}
+}
+
+// checkQuantityClick handles clicks on a quantity picker drawn at x, y and
+// reports whether the mouse is over it.
+func checkQuantityClick(x, y int) bool {
+	mouse := rl.GetMousePosition()
+	clicked := rl.IsMouseButtonPressed(rl.MouseLeftButton)
+	over := false
+
+	for i, opt := range quantityOptions {
+		if rl.CheckCollisionPointRec(mouse, quantityRect(x, y, i)) {
+			over = true
+			if clicked {
+				makeQuantity = opt.Count
+				enteringX = false
+			}
+		}
+	}
+	if rl.CheckCollisionPointRec(mouse, xRect(x, y)) {
+		over = true
+		if clicked {
+			enteringX = true
+			xInput = ""
+		}
+	}
+	return over
+}
+
+func drawQuantityPicker(x, y int) {
+	for i, opt := range quantityOptions {
+		drawQuantityButton(quantityRect(x, y, i), opt.Label, !enteringX && makeQuantity == opt.Count)
+	}
+	xLabel := "X"
+	switch {
+	case enteringX:
+		xLabel = xInput + "_"
+	case !slices.ContainsFunc(quantityOptions, func(o quantityOption) bool { return o.Count == makeQuantity }):
+		xLabel = strconv.Itoa(makeQuantity)
+	}
+	drawQuantityButton(xRect(x, y), xLabel, enteringX || xLabel != "X")
+}
+
+func stationRecipeRect(i int) rl.Rectangle {
+	return rl.NewRectangle(stationPanelX, float32(stationPanelY+(i+1)*(stationRowH+4)), stationRowWidth, stationRowH)
+}
+
+// CheckStationClick handles clicks on the station panel. It reports whether the mouse is over the panel so the click
+// doesn't also walk.
+func (p *Player) CheckStationClick() bool {
+	if openStation == StationNone {
+		return false
+	}
+	if !adjacent(p.CurrentTile(), openStationPos) {
+		closeStation()
+		return false
+	}
+
+	mouse := rl.GetMousePosition()
+	clicked := rl.IsMouseButtonPressed(rl.MouseLeftButton)
+	over := checkQuantityClick(stationPanelX, stationPanelY)
+
+	for i, recipe := range stationRecipes(openStation) {
+		if !rl.CheckCollisionPointRec(mouse, stationRecipeRect(i)) {
+			continue
+		}
+		over = true
+		if clicked {
+			if ok, problem := p.CanMake(recipe); ok {
+				p.Actions.Replace(NewCraftAction(p, recipe, makeQuantity, openStationPos))
+			} else {
+				showMessage(problem)
+			}
+		}
+	}
+	return over
+}
+
+func (p *Player) DrawStationUI() {
+	if openStation == StationNone {
+		return
+	}
+
+	drawQuantityPicker(stationPanelX, stationPanelY)
+
+	mouse := rl.GetMousePosition()
+	tooltip := ""
+	for i, recipe := range stationRecipes(openStation) {
+		rect := stationRecipeRect(i)
+		canMake, _ := p.CanMake(recipe)
+
+		bg := rl.Gray
+		if canMake {
+			bg = rl.LightGray
+		}
+		if rl.CheckCollisionPointRec(mouse, rect) {
+			bg = rl.DarkGray
+			tooltip = fmt.Sprintf("%s level %d\n", recipe.Skill, recipe.Level)
+			for _, input := range recipe.Inputs {
+				tooltip += fmt.Sprintf("%dx %s\n", input.Count, input.Def().Name)
+			}
+		}
+
+		rl.DrawRectangleRec(rect, bg)
+		rl.DrawRectangleLinesEx(rect, 1, rl.Black)
+		rl.DrawText(fmt.Sprintf("%s (%d)", recipe.Output.Def().Name, recipe.Level), int32(rect.X+6), int32(rect.Y+4), 16, rl.Black)
+	}
+
+	if tooltip != "" {
+		rl.DrawText(tooltip, int32(mouse.X+8), int32(mouse.Y+8), 16, rl.DarkBlue)
+	}
+}
+
+func drawQuantityButton(rect rl.Rectangle, label string, selected bool) {
+	bg := rl.LightGray
+	if selected {
+		bg = rl.Gold
+	}
+	rl.DrawRectangleRec(rect, bg)
+	rl.DrawRectangleLinesEx(rect, 1, rl.Black)
+	rl.DrawText(label, int32(rect.X+6), int32(rect.Y+4), 16, rl.Black)
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"slices"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Panel is an on-screen grid of item slots that items can be dragged
+// between. A new panel needs a case in panelSlotRect, panelItem,
+// panelSlotAt, canDrop and moveItem, and nothing else.
+type Panel int
+
+const (
+	PanelInventory Panel = iota
+	PanelEquipment
+	PanelBank    // Index is a slot in the bank, not a place in the view
+	PanelBankTab // Index is a tab; only ever a drop target
+)
+
+// Where the panels are drawn, below the hover line and HP along the top.
+const (
+	inventoryX, inventoryY = 10, 56
+	equipmentX, equipmentY = 400, 56
+	skillsX, skillsY       = 10, 240
+)
+
+// dragThreshold is how far, in pixels, the mouse has to move with the
+// button held before a click becomes a drag.
+const dragThreshold = 4
+
+// SlotRef is one slot in one panel. For the equipment panel Index is a
+// position in equipmentSlotOrder.
+type SlotRef struct {
+	Panel Panel
+	Index int
+}
+
+// DragState is an item held under the mouse button. It only becomes a drag
+// once the mouse has moved; until then releasing it is an ordinary click.
+type DragState struct {
+	From     SlotRef
+	Start    rl.Vector2
+	Dragging bool
+}
+
+var drag *DragState
+
+func isDragged(ref SlotRef) bool {
+	return drag != nil && drag.Dragging && drag.From == ref
+}
+
+// panelSlotRect is where ref is drawn.
+func panelSlotRect(ref SlotRef) rl.Rectangle {
+	switch ref.Panel {
+	case PanelEquipment:
+		return equipmentSlotRect(equipmentX, equipmentY, ref.Index)
+	case PanelBank:
+		rect, _ := bankSlotRect(slices.Index(bankView(), ref.Index))
+		return rect
+	case PanelBankTab:
+		return bankTabRect(ref.Index)
+	default:
+		return inventorySlotRect(inventoryX, inventoryY, ref.Index)
+	}
+}
+
+// panelItem is what's in ref.
+func panelItem(ref SlotRef) ItemSlot {
+	switch ref.Panel {
+	case PanelEquipment:
+		return player.Equipment.Slots[equipmentSlotOrder[ref.Index]]
+	case PanelBank:
+		s := player.Bank.Slots[ref.Index]
+		return ItemSlot{ID: s.ID, Count: s.Count}
+	case PanelBankTab:
+		return ItemSlot{}
+	default:
+		return player.Inventory.Get(ref.Index)
+	}
+}
+
+// panelSlotAt returns the slot under the mouse in any open panel.
+func panelSlotAt(mouse rl.Vector2) (SlotRef, bool) {
+	if !showInventory {
+		return SlotRef{}, false
+	}
+	for i := range player.Inventory.Slots() {
+		ref := SlotRef{PanelInventory, i}
+		if rl.CheckCollisionPointRec(mouse, panelSlotRect(ref)) {
+			return ref, true
+		}
+	}
+	for i := range equipmentSlotOrder {
+		ref := SlotRef{PanelEquipment, i}
+		if rl.CheckCollisionPointRec(mouse, panelSlotRect(ref)) {
+			return ref, true
+		}
+	}
+	if !bankOpen {
+		return SlotRef{}, false
+	}
+	for i, index := range bankView() {
+		if rect, visible := bankSlotRect(i); visible && rl.CheckCollisionPointRec(mouse, rect) {
+			return SlotRef{PanelBank, index}, true
+		}
+	}
+	for tab := range BankTabs {
+		if rl.CheckCollisionPointRec(mouse, bankTabRect(tab)) {
+			return SlotRef{PanelBankTab, tab}, true
+		}
+	}
+	return SlotRef{}, false
+}
+
+// canDrop reports whether the item in from can be dropped on to.
+func canDrop(from, to SlotRef) bool {
+	item, target := panelItem(from), panelItem(to)
+	switch {
+	case from == to:
+		return true
+	case from.Panel == PanelInventory && to.Panel == PanelInventory:
+		return true
+	case from.Panel == PanelInventory && to.Panel == PanelEquipment:
+		return item.Def().Slot == equipmentSlotOrder[to.Index]
+	case from.Panel == PanelEquipment && to.Panel == PanelInventory:
+		return target.Empty() || target.Def().Slot == equipmentSlotOrder[from.Index]
+	case from.Panel == PanelBank && to.Panel == PanelInventory:
+		return item.Count > 0
+	case to.Panel == PanelBank || to.Panel == PanelBankTab:
+		return true
+	}
+	return false
+}
+
+// moveItem moves the item in from to to, swapping with whatever is there.
+func moveItem(from, to SlotRef) {
+	if from == to || !canDrop(from, to) {
+		return
+	}
+	switch {
+	case from.Panel == PanelInventory && to.Panel == PanelInventory:
+		a, b := player.Inventory.Get(from.Index), player.Inventory.Get(to.Index)
+		player.Inventory.Set(from.Index, b)
+		player.Inventory.Set(to.Index, a)
+	case from.Panel == PanelInventory && to.Panel == PanelEquipment:
+		player.EquipFrom(from.Index)
+	case from.Panel == PanelEquipment && to.Panel == PanelInventory:
+		player.UnequipTo(equipmentSlotOrder[from.Index], to.Index)
+	case from.Panel == PanelBank && to.Panel == PanelInventory:
+		player.Withdraw(from.Index, makeQuantity)
+	case from.Panel == PanelBank && to.Panel == PanelBank:
+		player.Bank.Swap(from.Index, to.Index)
+	case from.Panel == PanelBank && to.Panel == PanelBankTab:
+		player.Bank.MoveToTab(from.Index, to.Index)
+	case from.Panel == PanelInventory:
+		player.Deposit(from.Index, player.Inventory.Get(from.Index).Count, depositTab(to))
+	case from.Panel == PanelEquipment:
+		player.DepositWorn(equipmentSlotOrder[from.Index], depositTab(to))
+	}
+}
+
+// depositTab is the tab an item dropped on to goes into if it's new to the
+// bank.
+func depositTab(to SlotRef) int {
+	if to.Panel == PanelBankTab {
+		return to.Index
+	}
+	return bankTab
+}
+
+// updateDrag picks up, carries and drops items between panels. A press and
+// release that never turned into a drag is returned as a click on that slot.
+func updateDrag() (clicked SlotRef, ok bool) {
+	mouse := rl.GetMousePosition()
+
+	if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		if ref, over := panelSlotAt(mouse); over && !panelItem(ref).Empty() {
+			drag = &DragState{From: ref, Start: mouse}
+		}
+		return SlotRef{}, false
+	}
+	if drag == nil {
+		return SlotRef{}, false
+	}
+
+	if !drag.Dragging && rl.Vector2Distance(mouse, drag.Start) > dragThreshold {
+		drag.Dragging = true
+	}
+	if !rl.IsMouseButtonReleased(rl.MouseLeftButton) {
+		return SlotRef{}, false
+	}
+
+	d := drag
+	drag = nil
+	if !d.Dragging {
+		return d.From, true
+	}
+	if to, over := panelSlotAt(mouse); over {
+		moveItem(d.From, to)
+	}
+	return SlotRef{}, false
+}
+
+// drawDrag highlights the slot under a dragged item and draws a ghost of
+// the item at the cursor.
+func drawDrag() {
+	if drag == nil || !drag.Dragging {
+		return
+	}
+	mouse := rl.GetMousePosition()
+
+	if to, over := panelSlotAt(mouse); over {
+		color := rl.Red
+		if canDrop(drag.From, to) {
+			color = rl.Green
+		}
+		rect := panelSlotRect(to)
+		rl.DrawRectangleRec(rect, rl.Fade(color, 0.3))
+		rl.DrawRectangleLinesEx(rect, 2, color)
+	}
+
+	ghost := rl.NewRectangle(mouse.X-slotSize/2, mouse.Y-slotSize/2, slotSize, slotSize)
+	drawItem(panelItem(drag.From), ghost, 0.6, false)
+}
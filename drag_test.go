@@ -0,0 +1,130 @@
+package main
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// weaponSlot is the weapon's position in the equipment panel.
+var weaponSlot = func() int {
+	for i, slot := range equipmentSlotOrder {
+		if slot == SlotWeapon {
+			return i
+		}
+	}
+	panic("no weapon slot")
+}()
+
+// dragPlayer sets up the global player with logs and an iron axe in the
+// first two inventory slots, a bronze axe wielded, and coins and an emptied
+// placeholder in the bank.
+func dragPlayer() {
+	player = NewPlayer(0, 0, NewMap(1, 1), rl.Texture2D{}, rl.Texture2D{})
+	player.Inventory.Set(0, ItemSlot{ID: "logs", Count: 1})
+	player.Inventory.Set(1, ItemSlot{ID: "iron_axe", Count: 1})
+	player.Equipment.Equip(SlotWeapon, ItemSlot{ID: "bronze_axe", Count: 1})
+	player.Bank.Deposit("coins", 100, 0)
+	player.Bank.Deposit("oak_logs", 1, 0)
+	player.Bank.Withdraw("oak_logs", 1)
+}
+
+func TestCanDrop(t *testing.T) {
+	loadItems(t)
+	dragPlayer()
+
+	inv := func(i int) SlotRef { return SlotRef{PanelInventory, i} }
+	weapon := SlotRef{PanelEquipment, weaponSlot}
+	head := SlotRef{PanelEquipment, 0}
+	coins, placeholder := SlotRef{PanelBank, 0}, SlotRef{PanelBank, 1}
+
+	tests := []struct {
+		name     string
+		from, to SlotRef
+		want     bool
+	}{
+		{"inventory to inventory", inv(0), inv(5), true},
+		{"logs to the weapon slot", inv(0), weapon, false},
+		{"axe to the weapon slot", inv(1), weapon, true},
+		{"axe to the head slot", inv(1), head, false},
+		{"unequip to an empty slot", weapon, inv(5), true},
+		{"unequip on to logs", weapon, inv(0), false},
+		{"unequip on to another axe swaps", weapon, inv(1), true},
+		{"withdraw", coins, inv(5), true},
+		{"withdraw a placeholder", placeholder, inv(5), false},
+		{"deposit", inv(0), placeholder, true},
+		{"deposit to a tab", inv(0), SlotRef{PanelBankTab, 3}, true},
+		{"deposit worn", weapon, SlotRef{PanelBankTab, 3}, true},
+		{"bank to equipment", coins, weapon, false},
+		{"on to itself", weapon, weapon, true},
+	}
+	for _, tt := range tests {
+		if got := canDrop(tt.from, tt.to); got != tt.want {
+			t.Errorf("%s: canDrop = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMoveItem(t *testing.T) {
+	loadItems(t)
+	saved := makeQuantity
+	t.Cleanup(func() { makeQuantity = saved })
+
+	tests := []struct {
+		name     string
+		from, to SlotRef
+		check    func() bool
+	}{
+		{
+			"swaps inventory slots",
+			SlotRef{PanelInventory, 0}, SlotRef{PanelInventory, 1},
+			func() bool {
+				return player.Inventory.Get(0).ID == "iron_axe" && player.Inventory.Get(1).ID == "logs"
+			},
+		},
+		{
+			"wields and swaps out the old weapon",
+			SlotRef{PanelInventory, 1}, SlotRef{PanelEquipment, weaponSlot},
+			func() bool {
+				return player.Equipment.Slots[SlotWeapon].ID == "iron_axe" && player.Inventory.Get(1).ID == "bronze_axe"
+			},
+		},
+		{
+			"refused drop leaves everything",
+			SlotRef{PanelInventory, 0}, SlotRef{PanelEquipment, weaponSlot},
+			func() bool {
+				return player.Equipment.Slots[SlotWeapon].ID == "bronze_axe" && player.Inventory.Get(0).ID == "logs"
+			},
+		},
+		{
+			"unequips into the slot dropped on",
+			SlotRef{PanelEquipment, weaponSlot}, SlotRef{PanelInventory, 9},
+			func() bool {
+				return player.Equipment.Slots[SlotWeapon].Empty() && player.Inventory.Get(9).ID == "bronze_axe"
+			},
+		},
+		{
+			"withdraws the picked quantity",
+			SlotRef{PanelBank, 0}, SlotRef{PanelInventory, 9},
+			func() bool {
+				return player.Inventory.Count("coins") == 5 && player.Bank.Count("coins") == 95
+			},
+		},
+		{
+			"deposits into the tab dropped on",
+			SlotRef{PanelInventory, 0}, SlotRef{PanelBankTab, 3},
+			func() bool {
+				i := player.Bank.find("logs")
+				return i >= 0 && player.Bank.Slots[i].Tab == 3 && player.Inventory.Count("logs") == 0
+			},
+		},
+	}
+	for _, tt := range tests {
+		dragPlayer()
+		makeQuantity = 5
+		moveItem(tt.from, tt.to)
+		if !tt.check() {
+			t.Errorf("%s: inventory %v, equipment %v, bank %v", tt.name, player.Inventory.Slots(), player.Equipment.Slots, player.Bank.Slots)
+		}
+	}
+}
@@ -8,20 +8,114 @@ type LootEntry struct {
 }
 
 type Enemy struct {
-	Pos       rl.Vector2
-	Texture   rl.Texture2D
-	Frame     rl.Rectangle
-	Health    int
-	MaxHealth int
-	Name      string
-	LootTable []LootEntry
+	ID         int // stays the same while the enemy lives; see enemyByID
+	Pos        rl.Vector2
+	PrevPos    rl.Vector2 // Pos before the last step, for interpolation
+	Texture    rl.Texture2D
+	Frame      rl.Rectangle
+	Health     int
+	MaxHealth  int
+	Name       string
+	Examine    string
+	LootTable  []LootEntry
+	Speed      float32 // pixels per second, 0 for enemies that stay put
+	AggroRange int     // tiles; the enemy ignores the player beyond this
+	Cowardly   bool    // follows the flee field instead of the chase field
+
+	moving    bool
+	next      Point
+	waitTimer float32
+}
+
+// nextEnemyID is the ID the next spawned enemy gets.
+var nextEnemyID = 1
+
+// spawnEnemy gives e a fresh ID and adds it to the world.
+func spawnEnemy(e Enemy) {
+	e.ID = nextEnemyID
+	nextEnemyID++
+	enemies = append(enemies, e)
+}
+
+// enemyByID finds a living enemy. Anything that holds on to an enemy past
+// the current step keeps its ID rather than a pointer, since the enemies
+// slice is compacted whenever one dies.
+func enemyByID(id int) *Enemy {
+	for i := range enemies {
+		if enemies[i].ID == id && enemies[i].Health > 0 {
+			return &enemies[i]
+		}
+	}
+	return nil
+}
+
+// enemyWaitTime is how long an enemy waits behind another entity before
+// trying to step around it.
+const enemyWaitTime = float32(0.4)
+
+// Tile returns the tile under the centre of the enemy's sprite.
+func (e *Enemy) Tile() Point {
+	return Point{
+		X: int(e.Pos.X+TileSize/2) / TileSize,
+		Y: int(e.Pos.Y+TileSize/2) / TileSize,
+	}
+}
+
+// Update walks the enemy one tile at a time along field. A step is always
+// finished before the next one is chosen so enemies never cut corners.
+func (e *Enemy) Update(t Tick, field *FlowField) {
+	e.PrevPos = e.Pos
+	if e.Speed <= 0 || field == nil {
+		return
+	}
+
+	if !e.moving {
+		here := e.Tile()
+		if e.AggroRange > 0 && int(heuristic(here, field.Target)) > e.AggroRange {
+			return
+		}
+		next, ok := field.NextStep(here)
+		if !ok {
+			return
+		}
+		if field.Map.Occupancy.IsOccupied(next) && next != field.Target {
+			// Wait for whoever is in the way, then settle for the next
+			// best free tile instead.
+			e.waitTimer += t.Dt
+			if e.waitTimer < enemyWaitTime {
+				return
+			}
+			next, ok = field.NextStepAvoiding(here, field.Map.Occupancy.IsOccupied)
+			if !ok {
+				return
+			}
+		}
+		e.waitTimer = 0
+		e.next = next
+		e.moving = true
+		// Reserve the tile so nobody else steps into it this frame.
+		field.Map.Occupancy.Add(next)
+	}
+
+	target := rl.NewVector2(float32(e.next.X*TileSize), float32(e.next.Y*TileSize))
+	dir := rl.Vector2Subtract(target, e.Pos)
+	step := e.Speed * t.Dt
+	if rl.Vector2Length(dir) <= step {
+		e.Pos = target
+		e.moving = false
+	} else {
+		e.Pos = rl.Vector2Add(e.Pos, rl.Vector2Scale(rl.Vector2Normalize(dir), step))
+	}
 }
 
-func (e *Enemy) Draw() {
-	rl.DrawTextureRec(e.Texture, e.Frame, e.Pos, rl.White)
+// Draw renders the enemy alpha of the way between its last two simulated
+// positions.
+func (e *Enemy) Draw(alpha float32) {
+	pos := rl.Vector2Lerp(e.PrevPos, e.Pos, alpha)
+	rl.DrawTextureRec(e.Texture, e.Frame, pos, rl.White)
 
 	// Optional health bar
 	barWidth := TileSize
-	rl.DrawRectangle(int32(e.Pos.X), int32(e.Pos.Y)-6, int32(barWidth), 4, rl.Red)
-	rl.DrawRectangle(int32(e.Pos.X), int32(e.Pos.Y)-6, int32(float32(barWidth)*float32(e.Health)/float32(e.MaxHealth)), 4, rl.Green)
+	rl.DrawRectangle(int32(pos.X), int32(pos.Y)-6, int32(barWidth), 4, rl.Red)
+	rl.DrawRectangle(int32(pos.X), int32(pos.Y)-6, int32(float32(barWidth)*float32(e.Health)/float32(e.MaxHealth)), 4, rl.Green)
 }
@@ -8,6 +8,7 @@ const (
 	SlotLegs   EquipmentSlot = "Legs"
 	SlotWeapon EquipmentSlot = "Weapon"
 	SlotShield EquipmentSlot = "Shield"
+	SlotAmmo   EquipmentSlot = "Ammo"
 )
 
 type Equipment struct {
@@ -22,25 +23,24 @@ func NewEquipment() *Equipment {
 			SlotLegs:   {},
 			SlotWeapon: {},
 			SlotShield: {},
+			SlotAmmo:   {},
 		},
 	}
 }
 
 func (e *Equipment) Equip(slot EquipmentSlot, item ItemSlot) ItemSlot {
-	e.Slots[slot] = ItemSlot{
-		Name:      item.Name,
-		Type:      item.Type,
-		Count:     1,
-		FrameRect: item.FrameRect,
+	// Ammo is worn as a whole stack, and more of the same joins it.
+	if slot == SlotAmmo {
+		if worn := e.Slots[slot]; worn.ID == item.ID {
+			item.Count += worn.Count
+		}
+		e.Slots[slot] = item
+		return ItemSlot{}
 	}
+	e.Slots[slot] = ItemSlot{ID: item.ID, Count: 1}
 	// Return the remaining stack (if any)
 	if item.Count > 1 {
-		return ItemSlot{
-			Name:      item.Name,
-			Type:      item.Type,
-			Count:     item.Count - 1,
-			FrameRect: item.FrameRect,
-		}
+		return ItemSlot{ID: item.ID, Count: item.Count - 1}
 	}
 	return ItemSlot{}
 }
@@ -51,18 +51,69 @@ func (e *Equipment) Unequip(slot EquipmentSlot) ItemSlot {
 	return item
 }
 
-func mapItemTypeToSlot(itemType string) EquipmentSlot {
-	switch itemType {
-	case "Weapon":
-		return SlotWeapon
-	case "Shield":
-		return SlotShield
-	case "Head":
-		return SlotHead
-	case "Body":
-		return SlotBody
-	case "Legs":
-		return SlotLegs
+// Weight is the total weight of everything equipped.
+func (e *Equipment) Weight() float32 {
+	var total float32
+	for _, item := range e.Slots {
+		if !item.Empty() {
+			total += item.Def().Weight * float32(item.Count)
+		}
+	}
+	return total
+}
+
+// EquipFrom wears the item in inventory slot index. Whatever it replaces
+// goes back into that slot if it is now free, or anywhere else there's room.
+func (p *Player) EquipFrom(index int) bool {
+	item := p.Inventory.Get(index)
+	slot := item.Def().Slot
+	if item.Empty() || slot == "" {
+		return false
+	}
+
+	// Arrows matching the ones worn are added to them, not swapped.
+	var swapped ItemSlot
+	if slot != SlotAmmo || p.Equipment.Slots[slot].ID != item.ID {
+		swapped = p.Equipment.Unequip(slot)
+	}
+	remaining := p.Equipment.Equip(slot, item)
+	p.Inventory.Set(index, remaining) // either empty or rest of stack
+	if swapped.Empty() {
+		return true
+	}
+	if remaining.Empty() {
+		p.Inventory.Set(index, swapped)
+	} else {
+		p.addOrDrop(swapped, p.CurrentTile())
+	}
+	return true
+}
+
+// UnequipTo takes off whatever is worn in slot and puts it in inventory slot
+// index, or anywhere with room if index is -1. An item already in the target
+// slot is only displaced if it can be worn in its place.
+func (p *Player) UnequipTo(slot EquipmentSlot, index int) bool {
+	item := p.Equipment.Slots[slot]
+	if item.Empty() {
+		return false
+	}
+
+	if index < 0 {
+		if !p.Inventory.CanAdd(item) {
+			showMessage("You don't have enough free inventory space to do that.")
+			return false
+		}
+		p.Inventory.Add(p.Equipment.Unequip(slot))
+		return true
+	}
+
+	target := p.Inventory.Get(index)
+	switch {
+	case target.Empty():
+		p.Inventory.Set(index, p.Equipment.Unequip(slot))
+		return true
+	case target.Def().Slot == slot:
+		return p.EquipFrom(index)
 	}
-	return ""
+	return false
 }
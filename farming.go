@@ -0,0 +1,464 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// farmingSaveFile is where patch state is kept between sessions. Crops keep
+// growing while the game is closed, worked out from the saved timestamps.
+const farmingSaveFile = "farming.json"
+
+// seedsPerPatch is how many seeds planting a patch takes.
+const seedsPerPatch = 3
+
+// Crop is something that can be grown in a farming patch.
+type Crop struct {
+	Seed          ItemID
+	Produce       ItemID
+	Level         int
+	PlantXP       float64
+	HarvestXP     float64
+	Stages        int           // growth stages before it can be harvested
+	StageTime     time.Duration // real time each stage takes
+	DiseaseChance float32       // chance per stage, before watering and compost
+	Color         rl.Color
+}
+
+var crops = []Crop{
+	{"potato_seed", "potato", 1, 8, 9, 4, time.Minute, 0.12, rl.Beige},
+	{"onion_seed", "onion", 5, 9.5, 10.5, 4, time.Minute, 0.12, rl.Gold},
+	{"cabbage_seed", "cabbage", 7, 10, 11.5, 4, time.Minute, 0.12, rl.Lime},
+	{"tomato_seed", "tomato", 12, 12.5, 14, 4, 2 * time.Minute, 0.15, rl.Red},
+}
+
+func cropBySeed(seed ItemID) *Crop {
+	for i := range crops {
+		if crops[i].Seed == seed {
+			return &crops[i]
+		}
+	}
+	return nil
+}
+
+func cropByProduce(id ItemID) *Crop {
+	for i := range crops {
+		if crops[i].Produce == id {
+			return &crops[i]
+		}
+	}
+	return nil
+}
+
+// Composts in order of strength; a patch's Compost field indexes this, with
+// 0 meaning none.
+var composts = []struct {
+	Item          ItemID
+	XP            float64
+	ExtraYield    int
+	DiseaseFactor float32
+}{
+	{"", 0, 0, 1},
+	{"compost", 18, 1, 0.5},
+	{"supercompost", 26, 2, 0.2},
+}
+
+type PatchState int
+
+const (
+	PatchWeeds PatchState = iota
+	PatchEmpty            // raked and ready for seeds
+	PatchGrowing
+	PatchDiseased
+	PatchDead
+	PatchReady
+)
+
+// Patch is the growth state of a farming patch tile.
+type Patch struct {
+	State      PatchState
+	Crop       ItemID    // seed that was planted, if any
+	Stage      int       // growth stages completed
+	StageStart time.Time // when the current stage began
+	Watered    bool      // watered since the current stage began
+	Compost    int       // index into composts
+	Harvests   int       // picks left once ready
+}
+
+func (pt *Patch) crop() *Crop {
+	return cropBySeed(pt.Crop)
+}
+
+func (pt *Patch) diseaseChance() float32 {
+	chance := pt.crop().DiseaseChance * composts[pt.Compost].DiseaseFactor
+	if pt.Watered {
+		chance *= 0.5
+	}
+	return chance
+}
+
+// Grow moves the patch on to where it should be at now, one stage at a time,
+// so a crop left for hours catches up in one call. A diseased crop that
+// isn't dug up by the end of its stage dies.
+func (pt *Patch) Grow(now time.Time) {
+	for pt.State == PatchGrowing || pt.State == PatchDiseased {
+		crop := pt.crop()
+		if crop == nil {
+			pt.clear()
+			return
+		}
+
+		next := pt.StageStart.Add(crop.StageTime)
+		if now.Before(next) {
+			return
+		}
+		pt.StageStart = next
+
+		if pt.State == PatchDiseased {
+			pt.State = PatchDead
+			return
+		}
+
+		pt.Stage++
+		if pt.Stage >= crop.Stages {
+			pt.State = PatchReady
+			pt.Harvests = 3 + sim.Rand.Intn(2) + composts[pt.Compost].ExtraYield
+			return
+		}
+		if sim.Rand.Float32() < pt.diseaseChance() {
+			pt.State = PatchDiseased
+		}
+		pt.Watered = false
+	}
+}
+
+// clear empties the patch back to raked soil.
+func (pt *Patch) clear() {
+	*pt = Patch{State: PatchEmpty}
+}
+
+func (pt *Patch) Draw(x, y int32) {
+	px, py := x*TileSize, y*TileSize
+
+	switch pt.State {
+	case PatchWeeds:
+		for i := int32(0); i < 4; i++ {
+			rl.DrawRectangle(px+4+i*7, py+10+(i%2)*8, 3, 10, rl.DarkGreen)
+		}
+	case PatchGrowing, PatchDiseased, PatchReady:
+		crop := pt.crop()
+		if crop == nil {
+			return
+		}
+		// Plants get taller with each stage.
+		height := int32(4 + (TileSize-12)*pt.Stage/crop.Stages)
+		color := rl.Green
+		if pt.State == PatchDiseased {
+			color = rl.Yellow
+		}
+		for i := int32(0); i < 3; i++ {
+			rl.DrawRectangle(px+6+i*9, py+TileSize-4-height, 3, height, color)
+		}
+		if pt.State == PatchReady {
+			for i := int32(0); i < 3; i++ {
+				rl.DrawCircle(px+7+i*9, py+TileSize-4-height, 4, crop.Color)
+			}
+		}
+		if pt.Watered {
+			rl.DrawRectangleLines(px+1, py+1, TileSize-2, TileSize-2, rl.SkyBlue)
+		}
+	case PatchDead:
+		for i := int32(0); i < 3; i++ {
+			rl.DrawRectangle(px+6+i*9, py+16, 3, 12, rl.Gray)
+		}
+	}
+}
+
+// TendPatchAt walks to a farming patch and does whatever it needs next:
+// raking, composting, planting, watering, harvesting or clearing.
+func (p *Player) TendPatchAt(tileX, tileY int) {
+	target := Point{tileX, tileY}
+	p.Actions.Replace(NewWalkAdjacentAction(p, target), NewFarmAction(p, target))
+}
+
+type farmJob int
+
+const (
+	jobRake farmJob = iota
+	jobCompost
+	jobPlant
+	jobWater
+	jobHarvest
+	jobClear
+)
+
+var farmJobTicks = map[farmJob]int{
+	jobRake:    4,
+	jobCompost: 2,
+	jobPlant:   3,
+	jobWater:   2,
+	jobHarvest: 3,
+	jobClear:   4,
+}
+
+var farmJobLabels = map[farmJob]string{
+	jobRake:    "Raking...",
+	jobCompost: "Composting...",
+	jobPlant:   "Planting...",
+	jobWater:   "Watering...",
+	jobHarvest: "Harvesting...",
+	jobClear:   "Clearing...",
+}
+
+// FarmAction does one job on a patch, except harvesting, which carries on
+// until the patch is picked clean.
+type FarmAction struct {
+	p     *Player
+	Patch Point
+	job   farmJob
+	seed  *Crop
+	ticks int
+}
+
+func NewFarmAction(p *Player, patch Point) *FarmAction {
+	return &FarmAction{p: p, Patch: patch}
+}
+
+func (f *FarmAction) patch() *Patch {
+	tile := f.p.Map.GetTile(f.Patch.X, f.Patch.Y)
+	if tile == nil {
+		return nil
+	}
+	return tile.Patch
+}
+
+func (f *FarmAction) has(item ItemID) bool {
+	return f.p.Inventory.HasItems([]ItemSlot{{ID: item, Count: 1}})
+}
+
+// nextJob works out what the patch needs doing, or why nothing can be done.
+func (f *FarmAction) nextJob(pt *Patch) (job farmJob, problem string) {
+	need := func(tool ItemID) string {
+		return fmt.Sprintf("You need %s %s to do that.", article(itemName(tool)), itemName(tool))
+	}
+
+	switch pt.State {
+	case PatchWeeds:
+		if !f.has("rake") {
+			return 0, need("rake")
+		}
+		return jobRake, ""
+	case PatchEmpty:
+		if pt.Compost == 0 && (f.has("compost") || f.has("supercompost")) {
+			return jobCompost, ""
+		}
+		if !f.has("seed_dibber") {
+			return 0, need("seed_dibber")
+		}
+		level := f.p.Skills.Level(SkillFarming)
+		problem = "You have no seeds to plant."
+		for _, slot := range f.p.Inventory.Slots() {
+			crop := cropBySeed(slot.ID)
+			if crop == nil {
+				continue
+			}
+			if level < crop.Level {
+				problem = fmt.Sprintf("You need a Farming level of %d to plant %s.", crop.Level, itemName(crop.Seed)+"s")
+				continue
+			}
+			if !f.p.Inventory.HasItems([]ItemSlot{{ID: crop.Seed, Count: seedsPerPatch}}) {
+				problem = fmt.Sprintf("You need %d %ss to plant this patch.", seedsPerPatch, itemName(crop.Seed))
+				continue
+			}
+			f.seed = crop
+			return jobPlant, ""
+		}
+		return 0, problem
+	case PatchGrowing:
+		if !pt.Watered && f.has("watering_can") {
+			return jobWater, ""
+		}
+		return 0, fmt.Sprintf("The %s is growing (stage %d of %d).", itemName(pt.crop().Produce), pt.Stage+1, pt.crop().Stages)
+	case PatchDiseased, PatchDead:
+		if !f.has("spade") {
+			return 0, need("spade")
+		}
+		return jobClear, ""
+	case PatchReady:
+		if !f.has("spade") {
+			return 0, need("spade")
+		}
+		return jobHarvest, ""
+	}
+	return 0, "Nothing interesting happens."
+}
+
+func (f *FarmAction) Start() bool {
+	pt := f.patch()
+	if pt == nil {
+		return false
+	}
+	pt.Grow(sim.Now())
+
+	job, problem := f.nextJob(pt)
+	if problem != "" {
+		showMessage(problem)
+		return false
+	}
+	f.job = job
+	f.ticks = farmJobTicks[job]
+	return true
+}
+
+func (f *FarmAction) Update(t Tick) ActionStatus {
+	pt := f.patch()
+	if pt == nil || !adjacent(f.p.CurrentTile(), f.Patch) {
+		return ActionFailed
+	}
+
+	if t.Game {
+		f.ticks--
+	}
+	if f.ticks > 0 {
+		return ActionRunning
+	}
+	f.ticks = farmJobTicks[f.job]
+
+	if f.job != jobHarvest {
+		return ActionDone
+	}
+
+	// Harvesting picks one at a time until the patch is empty.
+	if pt.State != PatchReady {
+		return ActionFailed
+	}
+	crop := pt.crop()
+	produce := ItemSlot{ID: crop.Produce, Count: 1}
+	if !f.p.Inventory.CanAdd(produce) {
+		showMessage("Your inventory is too full to harvest any more.")
+		return ActionDone
+	}
+	f.p.Inventory.Add(produce)
+	f.p.GainXP(SkillFarming, crop.HarvestXP)
+	pt.Harvests--
+	if pt.Harvests <= 0 {
+		showMessage("The patch is now empty.")
+		pt.clear()
+		return ActionDone
+	}
+	return ActionRunning
+}
+
+func (f *FarmAction) Cancel() {}
+
+func (f *FarmAction) Complete() {
+	pt := f.patch()
+	if pt == nil {
+		return
+	}
+
+	switch f.job {
+	case jobRake:
+		pt.State = PatchEmpty
+		f.p.addOrDrop(ItemSlot{ID: "weeds", Count: 1}, f.p.CurrentTile())
+		f.p.GainXP(SkillFarming, 4)
+	case jobCompost:
+		for i := len(composts) - 1; i > 0; i-- {
+			if f.has(composts[i].Item) {
+				f.p.Inventory.ConsumeItems([]ItemSlot{{ID: composts[i].Item, Count: 1}})
+				pt.Compost = i
+				f.p.GainXP(SkillFarming, composts[i].XP)
+				showMessage(fmt.Sprintf("You treat the patch with %s.", itemName(composts[i].Item)))
+				break
+			}
+		}
+	case jobPlant:
+		f.p.Inventory.ConsumeItems([]ItemSlot{{ID: f.seed.Seed, Count: seedsPerPatch}})
+		pt.State = PatchGrowing
+		pt.Crop = f.seed.Seed
+		pt.Stage = 0
+		pt.StageStart = sim.Now()
+		pt.Watered = false
+		f.p.GainXP(SkillFarming, f.seed.PlantXP)
+		showMessage(fmt.Sprintf("You plant %d %ss in the patch.", seedsPerPatch, itemName(f.seed.Seed)))
+	case jobWater:
+		pt.Watered = true
+		showMessage("You water the patch.")
+	case jobClear:
+		pt.clear()
+		showMessage("You clear the patch.")
+	}
+}
+
+func (f *FarmAction) Label() string { return farmJobLabels[f.job] }
+
+// updateFarming grows every patch on game ticks. Growth runs on sim's clock,
+// the wall clock in play, so this only has to notice when a stage has passed.
+func updateFarming(t Tick) {
+	if !t.Game {
+		return
+	}
+	now := sim.Now()
+	for y := range gameMap.Tiles {
+		for x := range gameMap.Tiles[y] {
+			if pt := gameMap.Tiles[y][x].Patch; pt != nil {
+				pt.Grow(now)
+			}
+		}
+	}
+}
+
+type savedPatch struct {
+	Pos   Point
+	Patch Patch
+}
+
+// SaveFarming writes every patch on the map to farmingSaveFile.
+func SaveFarming(m *Map) error {
+	var saved []savedPatch
+	for y := range m.Tiles {
+		for x := range m.Tiles[y] {
+			if pt := m.Tiles[y][x].Patch; pt != nil {
+				saved = append(saved, savedPatch{Pos: Point{x, y}, Patch: *pt})
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(farmingSaveFile, data, 0o644)
+}
+
+// LoadFarming restores saved patches onto the map's patch tiles and grows
+// them for the time the game was closed. A missing save file is not an error.
+func LoadFarming(m *Map) error {
+	data, err := os.ReadFile(farmingSaveFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var saved []savedPatch
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+
+	now := sim.Now()
+	for _, s := range saved {
+		tile := m.GetTile(s.Pos.X, s.Pos.Y)
+		if tile == nil || tile.Patch == nil {
+			continue
+		}
+		*tile.Patch = s.Patch
+		tile.Patch.Grow(now)
+	}
+	return nil
+}
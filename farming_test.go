@@ -0,0 +1,180 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+var farmStart = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// withTestCrops adds crops that never and always catch disease, for the
+// length of the test.
+func withTestCrops(t *testing.T) {
+	t.Helper()
+	saved := crops
+	crops = append(crops[:len(crops):len(crops)],
+		Crop{Seed: "healthy_seed", Produce: "potato", Stages: 4, StageTime: time.Minute, DiseaseChance: 0},
+		Crop{Seed: "sickly_seed", Produce: "potato", Stages: 4, StageTime: time.Minute, DiseaseChance: 1},
+	)
+	t.Cleanup(func() { crops = saved })
+}
+
+// atTime fixes sim's clock at now, with a seeded Rand.
+func atTime(now time.Time) {
+	sim = NewSim(1, func() time.Time { return now })
+}
+
+func planted(seed ItemID) Patch {
+	return Patch{State: PatchGrowing, Crop: seed, StageStart: farmStart}
+}
+
+func TestPatchGrow(t *testing.T) {
+	withTestCrops(t)
+	minute := time.Minute
+
+	tests := []struct {
+		name    string
+		patch   Patch
+		elapsed time.Duration
+		state   PatchState
+		stage   int
+		start   time.Duration // StageStart after growing, from farmStart
+	}{
+		{"just planted", planted("healthy_seed"), 0, PatchGrowing, 0, 0},
+		{"a second short of a stage", planted("healthy_seed"), minute - time.Second, PatchGrowing, 0, 0},
+		{"exactly one stage", planted("healthy_seed"), minute, PatchGrowing, 1, minute},
+		{"part way through stage three", planted("healthy_seed"), 3*minute - time.Second, PatchGrowing, 2, 2 * minute},
+		{"last stage done", planted("healthy_seed"), 4 * minute, PatchReady, 4, 4 * minute},
+		{"left for a day", planted("healthy_seed"), 24 * time.Hour, PatchReady, 4, 4 * minute},
+		{"diseased at the first stage", planted("sickly_seed"), minute, PatchDiseased, 1, minute},
+		{"dies a stage later", planted("sickly_seed"), 2 * minute, PatchDead, 1, 2 * minute},
+		{"dead crops stay dead", planted("sickly_seed"), 24 * time.Hour, PatchDead, 1, 2 * minute},
+		{"diseased before the game closed", Patch{State: PatchDiseased, Crop: "healthy_seed", Stage: 2, StageStart: farmStart}, 5 * minute, PatchDead, 2, minute},
+		{"unknown crop is cleared", planted("no_such_seed"), minute, PatchEmpty, 0, -1},
+		{"weeds don't grow", Patch{State: PatchWeeds}, time.Hour, PatchWeeds, 0, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atTime(farmStart.Add(tt.elapsed))
+			pt := tt.patch
+			pt.Grow(sim.Now())
+
+			if pt.State != tt.state || pt.Stage != tt.stage {
+				t.Fatalf("state %v stage %d, want state %v stage %d", pt.State, pt.Stage, tt.state, tt.stage)
+			}
+			if tt.start >= 0 && !pt.StageStart.Equal(farmStart.Add(tt.start)) {
+				t.Fatalf("stage started at %v, want %v", pt.StageStart.Sub(farmStart), tt.start)
+			}
+		})
+	}
+}
+
+func TestPatchWatering(t *testing.T) {
+	withTestCrops(t)
+	atTime(farmStart.Add(time.Minute))
+
+	pt := planted("healthy_seed")
+	pt.Watered = true
+	pt.Grow(sim.Now())
+	if pt.Watered {
+		t.Fatal("watering carried over into the next stage")
+	}
+}
+
+func TestPatchDiseaseChance(t *testing.T) {
+	tests := []struct {
+		compost int
+		watered bool
+		want    float32
+	}{
+		{0, false, 0.12},
+		{0, true, 0.06},
+		{1, false, 0.06},
+		{1, true, 0.03},
+		{2, false, 0.024},
+		{2, true, 0.012},
+	}
+	for _, tt := range tests {
+		pt := Patch{Crop: "potato_seed", Compost: tt.compost, Watered: tt.watered}
+		if got := pt.diseaseChance(); got < tt.want-1e-6 || got > tt.want+1e-6 {
+			t.Errorf("compost %d, watered %v: chance %v, want %v", tt.compost, tt.watered, got, tt.want)
+		}
+	}
+}
+
+func TestPatchHarvests(t *testing.T) {
+	withTestCrops(t)
+	for compost, c := range composts {
+		seen := map[int]bool{}
+		for seed := range int64(50) {
+			sim = NewSim(seed, func() time.Time { return farmStart.Add(4 * time.Minute) })
+			pt := planted("healthy_seed")
+			pt.Compost = compost
+			pt.Grow(sim.Now())
+			seen[pt.Harvests] = true
+		}
+		low, high := 3+c.ExtraYield, 4+c.ExtraYield
+		if len(seen) != 2 || !seen[low] || !seen[high] {
+			t.Errorf("compost %d: harvests %v, want %d and %d", compost, seen, low, high)
+		}
+	}
+}
+
+// TestPatchDiseaseRolls checks the real disease chance is rolled once per
+// stage: with potatoes at 12% about 1 - 0.88^3 of crops catch something on
+// the way to harvest.
+func TestPatchDiseaseRolls(t *testing.T) {
+	atTime(farmStart.Add(time.Hour))
+	diseased := 0
+	const n = 2000
+	for range n {
+		pt := planted("potato_seed")
+		pt.Grow(sim.Now())
+		if pt.State == PatchDead {
+			diseased++
+		}
+	}
+	if got := float64(diseased) / n; got < 0.27 || got > 0.35 {
+		t.Fatalf("%.2f of crops died, want about 0.32", got)
+	}
+}
+
+func TestFarmingSaveLoad(t *testing.T) {
+	withTestCrops(t)
+	t.Chdir(t.TempDir())
+	atTime(farmStart)
+
+	m := NewMap(5, 5)
+	m.SetTile(1, 1, TileFarmPatch)
+	m.SetTile(3, 1, TileFarmPatch)
+	*m.GetTile(1, 1).Patch = Patch{State: PatchGrowing, Crop: "healthy_seed", StageStart: farmStart, Watered: true, Compost: 2}
+	*m.GetTile(3, 1).Patch = Patch{State: PatchEmpty}
+	if err := SaveFarming(m); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two and a half stages pass while the game is closed. The second patch
+	// has since been paved over, so it is dropped.
+	atTime(farmStart.Add(150 * time.Second))
+	loaded := NewMap(5, 5)
+	loaded.SetTile(1, 1, TileFarmPatch)
+	if err := LoadFarming(loaded); err != nil {
+		t.Fatal(err)
+	}
+
+	pt := loaded.GetTile(1, 1).Patch
+	want := Patch{State: PatchGrowing, Crop: "healthy_seed", Stage: 2, StageStart: farmStart.Add(2 * time.Minute), Compost: 2}
+	if pt.State != want.State || pt.Stage != want.Stage || pt.Compost != want.Compost || pt.Watered || !pt.StageStart.Equal(want.StageStart) {
+		t.Fatalf("loaded %+v, want %+v", *pt, want)
+	}
+	if loaded.GetTile(3, 1).Patch != nil {
+		t.Fatal("a saved patch was loaded onto a tile that isn't one")
+	}
+}
+
+func TestLoadFarmingWithoutSave(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := LoadFarming(NewMap(5, 5)); err != nil {
+		t.Fatal(err)
+	}
+}
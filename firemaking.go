@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+)
+
+const (
+	// lightTicks is how many game ticks each attempt at lighting logs takes.
+	lightTicks = 3
+	// fireMinTicks and fireMaxTicks bound how long a fire burns, in game
+	// ticks, before it turns to ashes.
+	fireMinTicks = 100
+	fireMaxTicks = 200
+)
+
+// Burnable is a kind of log that can be lit.
+type Burnable struct {
+	Logs      ItemID
+	Level     int
+	XP        float64
+	LowChance float32 // chance to light per attempt at level 1
+}
+
+var burnables = []Burnable{
+	{"logs", 1, 40, 0.5},
+	{"oak_logs", 15, 60, 0.4},
+	{"willow_logs", 30, 90, 0.3},
+}
+
+func burnableByLogs(id ItemID) *Burnable {
+	for i := range burnables {
+		if burnables[i].Logs == id {
+			return &burnables[i]
+		}
+	}
+	return nil
+}
+
+// LightChance is the chance one attempt catches at the given Firemaking
+// level. It reaches certainty 50 levels above the requirement.
+func (b Burnable) LightChance(level int) float32 {
+	return min(1, b.LowChance+(1-b.LowChance)*float32(level-b.Level)/50)
+}
+
+// Fire is a lit fire on the map, counting down to ashes.
+type Fire struct {
+	Pos       Point
+	TicksLeft int
+}
+
+var fires []Fire
+
+// fireStepOrder is where the player tries to step after lighting a fire:
+// west first, as in RuneScape, then east, south and north.
+var fireStepOrder = []Point{{-1, 0}, {1, 0}, {0, 1}, {0, -1}}
+
+// TryLightFire starts lighting the logs in the given inventory slot on the
+// player's own tile.
+func (p *Player) TryLightFire(index int) {
+	p.Actions.Replace(NewLightFireAction(p, p.Inventory.Get(index).ID))
+}
+
+// LightFireAction uses a tinderbox on logs until they catch, leaving a fire
+// where the player stood and stepping them off it.
+type LightFireAction struct {
+	p     *Player
+	Logs  ItemID
+	burn  *Burnable
+	ticks int
+}
+
+func NewLightFireAction(p *Player, logs ItemID) *LightFireAction {
+	return &LightFireAction{p: p, Logs: logs}
+}
+
+func (a *LightFireAction) Start() bool {
+	a.burn = burnableByLogs(a.Logs)
+	if a.burn == nil {
+		return false
+	}
+	if !a.p.Inventory.HasItems([]ItemSlot{{ID: "tinderbox", Count: 1}}) {
+		showMessage("You need a tinderbox to light a fire.")
+		return false
+	}
+	if level := a.p.Skills.Level(SkillFiremaking); level < a.burn.Level {
+		showMessage(fmt.Sprintf("You need a Firemaking level of %d to burn %s.", a.burn.Level, itemName(a.Logs)))
+		return false
+	}
+
+	here := a.p.CurrentTile()
+	if tile := a.p.Map.GetTile(here.X, here.Y); tile == nil || tile.Type != TileGrass {
+		showMessage("You can't light a fire here.")
+		return false
+	}
+
+	// Stop walking so the fire goes where the player is standing.
+	a.p.Path = nil
+	a.ticks = lightTicks
+	return true
+}
+
+func (a *LightFireAction) Update(t Tick) ActionStatus {
+	if !a.p.Inventory.HasItems([]ItemSlot{{ID: a.Logs, Count: 1}}) {
+		return ActionFailed
+	}
+
+	if t.Game {
+		a.ticks--
+	}
+	if a.ticks > 0 {
+		return ActionRunning
+	}
+	a.ticks = lightTicks
+
+	if sim.Rand.Float32() >= a.burn.LightChance(a.p.Skills.Level(SkillFiremaking)) {
+		return ActionRunning
+	}
+	return ActionDone
+}
+
+func (a *LightFireAction) Cancel() {}
+
+func (a *LightFireAction) Complete() {
+	here := a.p.CurrentTile()
+
+	a.p.Inventory.ConsumeItems([]ItemSlot{{ID: a.Logs, Count: 1}})
+	a.p.Map.SetTile(here.X, here.Y, TileFire)
+	fires = append(fires, Fire{
+		Pos:       here,
+		TicksLeft: fireMinTicks + sim.Rand.Intn(fireMaxTicks-fireMinTicks+1),
+	})
+	a.p.GainXP(SkillFiremaking, a.burn.XP)
+	showMessage("The fire catches and the logs begin to burn.")
+
+	for _, d := range fireStepOrder {
+		aside := Point{here.X + d.X, here.Y + d.Y}
+		if a.p.Map.CanEnter(aside) {
+			a.p.MoveToTile(aside.X, aside.Y)
+			return
+		}
+	}
+}
+
+func (a *LightFireAction) Label() string { return "Lighting a fire..." }
+
+// updateFires burns fires down on game ticks and leaves ashes where each one
+// goes out.
+func updateFires(t Tick) {
+	if !t.Game {
+		return
+	}
+
+	lit := fires[:0]
+	for _, f := range fires {
+		f.TicksLeft--
+		if f.TicksLeft > 0 {
+			lit = append(lit, f)
+			continue
+		}
+		if tile := gameMap.GetTile(f.Pos.X, f.Pos.Y); tile != nil && tile.Type == TileFire {
+			gameMap.SetTile(f.Pos.X, f.Pos.Y, TileGrass)
+			DropGroundItem(f.Pos, ItemSlot{ID: "ashes", Count: 1}, nil)
+		}
+	}
+	fires = lit
+}
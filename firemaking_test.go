@@ -0,0 +1,128 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestLightChance(t *testing.T) {
+	tests := []struct {
+		logs  ItemID
+		level int
+		want  float32
+	}{
+		{"logs", 1, 0.5},
+		{"logs", 26, 0.75},
+		{"logs", 51, 1},
+		{"logs", 99, 1},
+		{"oak_logs", 15, 0.4},
+		{"oak_logs", 40, 0.7},
+		{"willow_logs", 30, 0.3},
+	}
+	for _, tt := range tests {
+		if got := burnableByLogs(tt.logs).LightChance(tt.level); math.Abs(float64(got-tt.want)) > 1e-6 {
+			t.Errorf("%s at level %d: %v, want %v", tt.logs, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestLightFire(t *testing.T) {
+	loadItems(t)
+	sim = NewSim(1, time.Now)
+
+	tests := []struct {
+		name  string
+		walls []Point
+		aside Point // where the player steps off the fire to
+	}{
+		{"steps west", nil, Point{0, 1}},
+		{"east if west is blocked", []Point{{0, 1}}, Point{2, 1}},
+		{"then south", []Point{{0, 1}, {2, 1}}, Point{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMap(3, 3)
+			for _, w := range tt.walls {
+				m.SetTile(w.X, w.Y, TileWall)
+			}
+			p := NewPlayer(tilePos(1, 1).X, tilePos(1, 1).Y, m, rl.Texture2D{}, rl.Texture2D{})
+			p.SmoothPaths = false
+			p.Skills.AddXP(SkillFiremaking, XPForLevel(99))
+			p.Inventory.Add(ItemSlot{ID: "tinderbox", Count: 1})
+			p.Inventory.Add(ItemSlot{ID: "logs", Count: 1})
+			fires = nil
+
+			p.Actions.Push(NewLightFireAction(&p, "logs"))
+			for range lightTicks {
+				p.Actions.Update(Tick{Dt: MoveStep, Game: true})
+			}
+			if !p.Actions.Idle() {
+				t.Fatal("still lighting at level 99")
+			}
+			if m.GetTile(1, 1).Type != TileFire || len(fires) != 1 {
+				t.Fatalf("tile is %d, %d fires", m.GetTile(1, 1).Type, len(fires))
+			}
+			if left := fires[0].TicksLeft; left < fireMinTicks || left > fireMaxTicks {
+				t.Fatalf("fire lasts %d ticks", left)
+			}
+			if p.Inventory.Count("logs") != 0 {
+				t.Fatal("logs weren't used up")
+			}
+			if len(p.Path) == 0 || p.Path[len(p.Path)-1] != tt.aside {
+				t.Fatalf("stepping off the fire along %v, want to %v", p.Path, tt.aside)
+			}
+		})
+	}
+}
+
+func TestLightFireNeeds(t *testing.T) {
+	loadItems(t)
+	tests := []struct {
+		name  string
+		logs  ItemID
+		level int
+		items []ItemSlot
+		tile  int
+	}{
+		{"no tinderbox", "logs", 1, []ItemSlot{{ID: "logs", Count: 1}}, TileGrass},
+		{"level too low", "oak_logs", 14, []ItemSlot{{ID: "tinderbox", Count: 1}, {ID: "oak_logs", Count: 1}}, TileGrass},
+		{"not burnable", "coal", 99, []ItemSlot{{ID: "tinderbox", Count: 1}, {ID: "coal", Count: 1}}, TileGrass},
+		{"standing on a fire", "logs", 1, []ItemSlot{{ID: "tinderbox", Count: 1}, {ID: "logs", Count: 1}}, TileFire},
+	}
+	for _, tt := range tests {
+		m := NewMap(3, 3)
+		m.SetTile(1, 1, tt.tile)
+		p := NewPlayer(tilePos(1, 1).X, tilePos(1, 1).Y, m, rl.Texture2D{}, rl.Texture2D{})
+		p.Skills.AddXP(SkillFiremaking, XPForLevel(tt.level))
+		for _, item := range tt.items {
+			p.Inventory.Add(item)
+		}
+		if NewLightFireAction(&p, tt.logs).Start() {
+			t.Errorf("%s: lit a fire", tt.name)
+		}
+	}
+}
+
+func TestFiresBurnOut(t *testing.T) {
+	loadItems(t)
+	gameMap = NewMap(3, 3)
+	gameMap.SetTile(1, 1, TileFire)
+	fires = []Fire{{Pos: Point{1, 1}, TicksLeft: 2}}
+	groundItems = nil
+
+	updateFires(Tick{Dt: MoveStep})
+	updateFires(Tick{Dt: MoveStep, Game: true})
+	if len(fires) != 1 || gameMap.GetTile(1, 1).Type != TileFire {
+		t.Fatal("fire went out early")
+	}
+	updateFires(Tick{Dt: MoveStep, Game: true})
+	if len(fires) != 0 || gameMap.GetTile(1, 1).Type != TileGrass {
+		t.Fatal("fire still burning")
+	}
+	if len(groundItems) != 1 || groundItems[0].Item.ID != "ashes" || groundItems[0].Owner != nil {
+		t.Fatalf("left %v on the ground, want public ashes", groundItems)
+	}
+}
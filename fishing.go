@@ -0,0 +1,53 @@
+package main
+
+const (
+	// fishingSpotChance is the share of generated water tiles that start
+	// with a fishing spot.
+	fishingSpotChance = 0.4
+	// fishingSpotMoveChance is the chance, each game tick, that a given spot
+	// drifts to another stretch of water. At 600 ms ticks that is roughly
+	// once a minute.
+	fishingSpotMoveChance = 0.01
+	// fishingSpotMoveRange is how far, in tiles, a spot can drift.
+	fishingSpotMoveRange = 4
+)
+
+// UpdateFishingSpots moves fishing spots around now and then, so anglers have
+// to follow the fish. Spots only move on game ticks.
+func (m *Map) UpdateFishingSpots(t Tick) {
+	if !t.Game {
+		return
+	}
+
+	var spots []Point
+	for y := 0; y < m.Height; y++ {
+		for x := 0; x < m.Width; x++ {
+			if m.Tiles[y][x].Resource == ResourceFishingSpot {
+				spots = append(spots, Point{x, y})
+			}
+		}
+	}
+
+	for _, spot := range spots {
+		if sim.Rand.Float64() >= fishingSpotMoveChance {
+			continue
+		}
+
+		var free []Point
+		for y := spot.Y - fishingSpotMoveRange; y <= spot.Y+fishingSpotMoveRange; y++ {
+			for x := spot.X - fishingSpotMoveRange; x <= spot.X+fishingSpotMoveRange; x++ {
+				tile := m.GetTile(x, y)
+				if tile != nil && tile.Type == TileWater && tile.Resource == ResourceNone {
+					free = append(free, Point{x, y})
+				}
+			}
+		}
+		if len(free) == 0 {
+			continue
+		}
+
+		dest := free[sim.Rand.Intn(len(free))]
+		m.Tiles[spot.Y][spot.X].Resource = ResourceNone
+		m.Tiles[dest.Y][dest.X].Resource = ResourceFishingSpot
+	}
+}
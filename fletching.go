@@ -0,0 +1,93 @@
+package main
+
+const fletchTicks = 3
+
+func fletch(output ItemID, count, level int, xp float64, inputs ...ItemSlot) Recipe {
+	return Recipe{
+		Inputs: inputs,
+		Output: ItemSlot{ID: output, Count: count},
+		Skill:  SkillFletching,
+		XP:     xp,
+		Level:  level,
+		Ticks:  fletchTicks,
+	}
+}
+
+// cut is a fletching recipe that carves logs with a knife.
+func cut(output ItemID, count int, logs ItemID, level int, xp float64) Recipe {
+	r := fletch(output, count, level, xp, ItemSlot{ID: logs, Count: 1})
+	r.Tools = []ItemID{"knife"}
+	return r
+}
+
+// stringBow puts a bow string on an unstrung bow.
+func stringBow(bow ItemID, level int, xp float64) Recipe {
+	return fletch(bow, 1, level, xp, ItemSlot{ID: bow + "_u", Count: 1}, ItemSlot{ID: "bow_string", Count: 1})
+}
+
+// tip finishes a batch of 15 headless arrows with arrowtips.
+func tip(metal string, level int, xp float64) Recipe {
+	return fletch(ItemID(metal+"_arrow"), 15, level, xp,
+		ItemSlot{ID: "headless_arrow", Count: 15},
+		ItemSlot{ID: ItemID(metal + "_arrowtips"), Count: 15})
+}
+
+var fletchingRecipes = []Recipe{
+	cut("arrow_shaft", 15, "logs", 1, 5),
+	cut("shortbow_u", 1, "logs", 5, 5),
+	cut("longbow_u", 1, "logs", 10, 10),
+	cut("oak_shortbow_u", 1, "oak_logs", 20, 16.5),
+	cut("oak_longbow_u", 1, "oak_logs", 25, 25),
+	cut("willow_shortbow_u", 1, "willow_logs", 35, 33.3),
+	cut("willow_longbow_u", 1, "willow_logs", 40, 41.5),
+
+	stringBow("shortbow", 5, 5),
+	stringBow("longbow", 10, 10),
+	stringBow("oak_shortbow", 20, 16.5),
+	stringBow("oak_longbow", 25, 25),
+	stringBow("willow_shortbow", 35, 33.3),
+	stringBow("willow_longbow", 40, 41.5),
+
+	fletch("headless_arrow", 15, 1, 15, ItemSlot{ID: "arrow_shaft", Count: 15}, ItemSlot{ID: "feather", Count: 15}),
+	tip("bronze", 1, 19.5),
+	tip("iron", 15, 37.5),
+	tip("steel", 30, 75),
+}
+
+// Bow is a ranged weapon. Range is in tiles.
+type Bow struct {
+	Item  ItemID
+	Level int // Ranged level needed to fire it
+	Range int
+	Bonus int // extra damage on top of the arrow's
+}
+
+var bows = []Bow{
+	{"shortbow", 1, 7, 0},
+	{"longbow", 1, 9, 1},
+	{"oak_shortbow", 5, 7, 1},
+	{"oak_longbow", 5, 9, 2},
+	{"willow_shortbow", 20, 7, 2},
+	{"willow_longbow", 20, 9, 3},
+}
+
+func bowByItem(id ItemID) *Bow {
+	for i := range bows {
+		if bows[i].Item == id {
+			return &bows[i]
+		}
+	}
+	return nil
+}
+
+// arrowDamage is the most each kind of arrow can hit for.
+var arrowDamage = map[ItemID]int{
+	"bronze_arrow": 7,
+	"iron_arrow":   9,
+	"steel_arrow":  11,
+}
+
+func isArrow(id ItemID) bool {
+	_, ok := arrowDamage[id]
+	return ok
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"container/heap"
+	"math"
+)
+
+// fleeFactor scales a chase field before it is relaxed again into a flee
+// field. Going slightly past -1 makes fleeing agents prefer open ground over
+// running into the nearest dead end.
+const fleeFactor = -1.2
+
+// FlowField is a Dijkstra map over the walkable tiles of a Map. Every tile
+// stores its distance to Target, so any number of agents can pick their next
+// step by rolling downhill instead of running FindPath each.
+type FlowField struct {
+	Map    *Map
+	Target Point
+	Flee   bool
+
+	dist    [][]float64
+	version int
+	built   bool
+}
+
+func NewFlowField(m *Map, flee bool) *FlowField {
+	return &FlowField{Map: m, Flee: flee}
+}
+
+// Update rebuilds the field when the target has moved to another tile or the
+// map has changed since the last build. It reports whether a rebuild happened.
+func (f *FlowField) Update(target Point) bool {
+	if f.built && f.Target == target && f.version == f.Map.Version {
+		return false
+	}
+
+	f.Target = target
+	f.version = f.Map.Version
+	f.rebuild()
+	f.built = true
+	return true
+}
+
+func (f *FlowField) rebuild() {
+	f.dist = make([][]float64, f.Map.Height)
+	for y := range f.dist {
+		f.dist[y] = make([]float64, f.Map.Width)
+		for x := range f.dist[y] {
+			f.dist[y][x] = math.Inf(1)
+		}
+	}
+
+	if f.Map.GetTile(f.Target.X, f.Target.Y) == nil {
+		return
+	}
+
+	f.dist[f.Target.Y][f.Target.X] = 0
+	f.relax([]Point{f.Target})
+
+	if f.Flee {
+		var seeds []Point
+		for y := range f.dist {
+			for x := range f.dist[y] {
+				if !math.IsInf(f.dist[y][x], 1) {
+					f.dist[y][x] *= fleeFactor
+					seeds = append(seeds, Point{x, y})
+				}
+			}
+		}
+		f.relax(seeds)
+	}
+}
+
+// relax runs Dijkstra outward from seeds, lowering any tile that can be
+// reached more cheaply through a neighbour.
+func (f *FlowField) relax(seeds []Point) {
+	open := make(PriorityQueue, 0, len(seeds))
+	heap.Init(&open)
+	for _, s := range seeds {
+		heap.Push(&open, &Node{Point: s, F: f.dist[s.Y][s.X]})
+	}
+
+	for open.Len() > 0 {
+		current := heap.Pop(&open).(*Node)
+		if current.F > f.dist[current.Y][current.X] {
+			continue // stale entry
+		}
+
+		for _, next := range neighbors(current.Point) {
+			tile := f.Map.GetTile(next.X, next.Y)
+			if tile == nil || !tile.IsWalkable() {
+				continue
+			}
+
+			cost := current.F + 1
+			if cost < f.dist[next.Y][next.X] {
+				f.dist[next.Y][next.X] = cost
+				heap.Push(&open, &Node{Point: next, F: cost})
+			}
+		}
+	}
+}
+
+// Distance returns the field value at p, or +Inf if p is off the map or
+// cannot reach the target.
+func (f *FlowField) Distance(p Point) float64 {
+	if f.dist == nil || f.Map.GetTile(p.X, p.Y) == nil {
+		return math.Inf(1)
+	}
+	return f.dist[p.Y][p.X]
+}
+
+// NextStep returns the neighbouring tile an agent standing on from should move
+// to. ok is false when from is already at a local minimum (on the target for a
+// chase field, or cornered for a flee field) or cannot reach the target.
+func (f *FlowField) NextStep(from Point) (next Point, ok bool) {
+	return f.NextStepAvoiding(from, nil)
+}
+
+// NextStepAvoiding is NextStep but skips any neighbour for which blocked
+// returns true, settling for the best downhill tile that is still free.
+func (f *FlowField) NextStepAvoiding(from Point, blocked func(Point) bool) (next Point, ok bool) {
+	best := f.Distance(from)
+	if math.IsInf(best, 1) {
+		return Point{}, false
+	}
+
+	for _, n := range neighbors(from) {
+		if blocked != nil && blocked(n) {
+			continue
+		}
+		if d := f.Distance(n); d < best {
+			best = d
+			next = n
+			ok = true
+		}
+	}
+	return next, ok
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestChaseFieldMatchesAStar(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for i := range 300 {
+		m := randomMap(rng, 5+rng.Intn(15), 5+rng.Intn(15), 0.3*rng.Float64(), 0)
+		target := randomPoint(rng, m)
+		if !m.CanEnter(target) {
+			continue
+		}
+		f := NewFlowField(m, false)
+		f.Update(target)
+
+		for y := range m.Height {
+			for x := range m.Width {
+				from := Point{x, y}
+				if !m.CanEnter(from) {
+					continue
+				}
+				path := AStar{}.FindPath(from, target, m)
+				if path == nil {
+					if !math.IsInf(f.Distance(from), 1) {
+						t.Fatalf("map %d: %v can't reach %v but has distance %v", i, from, target, f.Distance(from))
+					}
+					continue
+				}
+				if want := float64(len(path) - 1); f.Distance(from) != want {
+					t.Fatalf("map %d: distance from %v is %v, A* says %v", i, from, f.Distance(from), want)
+				}
+
+				// Rolling downhill gets there in that many steps.
+				steps := 0
+				for p := from; p != target; steps++ {
+					next, ok := f.NextStep(p)
+					if !ok || abs(next.X-p.X)+abs(next.Y-p.Y) != 1 || !m.CanEnter(next) {
+						t.Fatalf("map %d: bad step from %v to %v", i, p, next)
+					}
+					p = next
+				}
+				if steps != len(path)-1 {
+					t.Fatalf("map %d: took %d steps from %v, want %d", i, steps, from, len(path)-1)
+				}
+			}
+		}
+	}
+}
+
+func TestFleeFieldRunsAway(t *testing.T) {
+	m := NewMap(15, 15)
+	player := Point{7, 7}
+	chase, flee := NewFlowField(m, false), NewFlowField(m, true)
+	chase.Update(player)
+	flee.Update(player)
+
+	for y := range m.Height {
+		for x := range m.Width {
+			p := Point{x, y}
+			if got, limit := flee.Distance(p), fleeFactor*chase.Distance(p); got > limit {
+				t.Fatalf("flee value at %v is %v, above the scaled chase value %v", p, got, limit)
+			}
+		}
+	}
+
+	// Starting next to the player, every step takes the agent further away
+	// until it is cornered.
+	p := Point{8, 7}
+	for range 100 {
+		next, ok := flee.NextStep(p)
+		if !ok {
+			break
+		}
+		if chase.Distance(next) <= chase.Distance(p) {
+			t.Fatalf("fleeing from %v to %v doesn't get further from the player", p, next)
+		}
+		p = next
+	}
+	if _, ok := flee.NextStep(p); ok {
+		t.Fatal("still fleeing after 100 steps on a 15x15 map")
+	}
+	if chase.Distance(p) < 12 {
+		t.Fatalf("cornered at %v, only %v from the player", p, chase.Distance(p))
+	}
+}
+
+func TestFlowFieldUpdate(t *testing.T) {
+	m := NewMap(5, 5)
+	f := NewFlowField(m, false)
+
+	if !f.Update(Point{1, 1}) {
+		t.Fatal("first Update didn't build")
+	}
+	if f.Update(Point{1, 1}) {
+		t.Fatal("rebuilt with nothing changed")
+	}
+	if !f.Update(Point{2, 1}) {
+		t.Fatal("didn't rebuild when the target moved")
+	}
+	m.SetTile(3, 3, TileWall)
+	if !f.Update(Point{2, 1}) {
+		t.Fatal("didn't rebuild when the map changed")
+	}
+	if !math.IsInf(f.Distance(Point{3, 3}), 1) {
+		t.Fatal("a wall has a finite distance")
+	}
+}
+
+func TestNextStepAvoiding(t *testing.T) {
+	m := NewMap(5, 5)
+	f := NewFlowField(m, false)
+	f.Update(Point{4, 2})
+
+	// Straight ahead is blocked and no other neighbour is closer, so the
+	// agent waits rather than sidestepping.
+	from := Point{2, 2}
+	blocked := func(p Point) bool { return p == Point{3, 2} }
+	if next, ok := f.NextStepAvoiding(from, blocked); ok {
+		t.Fatalf("stepped to %v, but no free neighbour is downhill", next)
+	}
+
+	from = Point{2, 1}
+	next, ok := f.NextStepAvoiding(from, func(p Point) bool { return p == Point{3, 1} })
+	if !ok || next != (Point{2, 2}) {
+		t.Fatalf("stepped to %v (%v), want round by 2,2", next, ok)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GatherSetting is what gathering from a kind of tile has in common across
+// tiers. What it yields comes from the tile's Resource.
+type GatherSetting struct {
+	Label  string
+	Verb   string
+	Option string     // as it appears in the right-click menu
+	Tools  []ToolKind // any one of these will do
+	Skill  Skill
+	Ticks  int // game ticks per attempt
+}
+
+var gatherSettings = map[int]GatherSetting{
+	TileTree:  {"Chopping...", "chop", "Chop down", []ToolKind{ToolAxe}, SkillWoodcutting, 3},
+	TileRock:  {"Mining...", "mine", "Mine", []ToolKind{ToolPickaxe}, SkillMining, 4},
+	TileWater: {"Fishing...", "fish at", "Net", []ToolKind{ToolNet, ToolFishingRod}, SkillFishing, 5},
+}
+
+// TryGatherAt replaces whatever the player is doing with walking up to the
+// tile and gathering from it.
+func (p *Player) TryGatherAt(tileX, tileY int) {
+	tile := p.Map.GetTile(tileX, tileY)
+	if tile == nil || !tile.IsGatherable() {
+		fmt.Println("Tile not gatherable")
+		return
+	}
+
+	target := Point{tileX, tileY}
+	p.Actions.Replace(NewWalkAdjacentAction(p, target), NewGatherAction(p, target))
+}
+
+// GatherAction chops, mines or fishes a tile the player is standing next to.
+// It keeps going, one attempt every few game ticks, until the resource is
+// used up, the inventory is full or the player does something else.
+type GatherAction struct {
+	p        *Player
+	Target   Point
+	setting  GatherSetting
+	resource Resource
+	tool     *Tool
+	ticks    int
+}
+
+func NewGatherAction(p *Player, target Point) *GatherAction {
+	return &GatherAction{p: p, Target: target}
+}
+
+func (g *GatherAction) Start() bool {
+	tile := g.p.Map.GetTile(g.Target.X, g.Target.Y)
+	if tile == nil || !tile.IsGatherable() {
+		fmt.Println("Invalid gather target")
+		return false
+	}
+	if !adjacent(g.p.CurrentTile(), g.Target) {
+		fmt.Println("Target not adjacent — skipping gather")
+		return false
+	}
+
+	g.setting = gatherSettings[tile.Type]
+	g.resource = resources[tile.Resource]
+
+	if g.p.Skills.Level(g.setting.Skill) < g.resource.Level {
+		showMessage(fmt.Sprintf("You need a %s level of %d to %s this %s.", g.setting.Skill, g.resource.Level, g.setting.Verb, strings.ToLower(g.resource.Name)))
+		return false
+	}
+
+	tool, problem := g.p.BestTool(g.setting.Tools)
+	if tool == nil {
+		showMessage(problem)
+		return false
+	}
+	g.tool = tool
+
+	if !g.p.Inventory.CanAdd(g.yield()) {
+		showMessage("Your inventory is too full to hold any more " + itemName(g.resource.Item) + ".")
+		return false
+	}
+
+	g.ticks = g.attemptTicks()
+	return true
+}
+
+// attemptTicks is how many game ticks each attempt takes with the current
+// tool. Even the best tool can't go below one.
+func (g *GatherAction) attemptTicks() int {
+	return max(1, g.setting.Ticks-g.tool.TickBonus)
+}
+
+// successChance is the chance one attempt succeeds, which rises with skill
+// level and tool tier.
+func (g *GatherAction) successChance() float32 {
+	level := g.p.Skills.Level(g.setting.Skill)
+	return min(1, g.resource.SuccessChance(level)+g.tool.SuccessBonus)
+}
+
+func (g *GatherAction) yield() ItemSlot {
+	return ItemSlot{ID: g.resource.Item, Count: 1}
+}
+
+func (g *GatherAction) Update(t Tick) ActionStatus {
+	// Someone else may have cleared the resource, or the fish moved on.
+	if tile := g.p.Map.GetTile(g.Target.X, g.Target.Y); tile == nil || !tile.IsGatherable() {
+		if tile != nil && tile.Type == TileWater {
+			showMessage("The fishing spot has moved.")
+		}
+		return ActionDone
+	}
+
+	if t.Game {
+		g.ticks--
+	}
+	if g.ticks > 0 {
+		return ActionRunning
+	}
+	g.ticks = g.attemptTicks()
+
+	if sim.Rand.Float32() >= g.successChance() {
+		return ActionRunning
+	}
+
+	g.p.addOrDrop(g.yield(), g.p.CurrentTile())
+	g.p.GainXP(g.setting.Skill, g.resource.XP)
+
+	for _, extra := range g.resource.Secondary {
+		if sim.Rand.Float32() < extra.Chance {
+			showMessage("You find a " + itemName(extra.Item) + "!")
+			g.p.addOrDrop(ItemSlot{ID: extra.Item, Count: 1}, g.p.CurrentTile())
+		}
+	}
+
+	if sim.Rand.Float32() < g.resource.DepleteChance {
+		g.p.Map.SetTile(g.Target.X, g.Target.Y, TileGrass)
+		return ActionDone
+	}
+
+	if !g.p.Inventory.CanAdd(g.yield()) {
+		showMessage("Your inventory is too full to hold any more " + itemName(g.resource.Item) + ".")
+		return ActionDone
+	}
+	return ActionRunning
+}
+
+func (g *GatherAction) Cancel() {}
+
+func (g *GatherAction) Complete() {}
+
+func (g *GatherAction) Label() string { return g.setting.Label }
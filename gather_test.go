@@ -0,0 +1,118 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// gatherer stands at 0,0 next to a tile at 1,0 of the given type and
+// resource, with tool in hand and only free slots left in the inventory.
+func gatherer(tileType, resource int, tool ItemID, level, free int) *Player {
+	m := NewMap(3, 1)
+	m.SetTile(1, 0, tileType)
+	m.GetTile(1, 0).Resource = resource
+	p := NewPlayer(0, 0, m, rl.Texture2D{}, rl.Texture2D{})
+	p.Inventory = fullInventory(free)
+	p.Equipment.Equip(SlotWeapon, ItemSlot{ID: tool, Count: 1})
+	for skill := range skillCount {
+		p.Skills.AddXP(skill, XPForLevel(level))
+	}
+	return &p
+}
+
+// gatherTicks runs the player's actions a game tick at a time until they go
+// idle, and returns how many ticks that took.
+func gatherTicks(t *testing.T, p *Player) int {
+	t.Helper()
+	for n := 1; n <= 10000; n++ {
+		p.Actions.Update(Tick{Dt: MoveStep, Game: true})
+		if p.Actions.Idle() {
+			return n
+		}
+	}
+	t.Fatal("still gathering after 10000 ticks")
+	return 0
+}
+
+func TestGatherAttemptTicks(t *testing.T) {
+	loadItems(t)
+	tests := []struct {
+		tileType, resource int
+		tool               ItemID
+		want               int
+	}{
+		{TileTree, ResourceTree, "bronze_axe", 3},
+		{TileTree, ResourceTree, "steel_axe", 2},
+		{TileTree, ResourceTree, "rune_axe", 1},
+		{TileRock, ResourceCopper, "rune_pickaxe", 2},
+		{TileWater, ResourceFishingSpot, "small_fishing_net", 5},
+	}
+	for _, tt := range tests {
+		p := gatherer(tt.tileType, tt.resource, tt.tool, 99, 10)
+		g := NewGatherAction(p, Point{1, 0})
+		if !g.Start() {
+			t.Fatalf("%s: didn't start", tt.tool)
+		}
+		if got := g.attemptTicks(); got != tt.want {
+			t.Errorf("%s: %d ticks per attempt, want %d", tt.tool, got, tt.want)
+		}
+	}
+}
+
+func TestGatherRepeatsUntilFull(t *testing.T) {
+	loadItems(t)
+	sim = NewSim(1, time.Now)
+	groundItems = nil
+
+	// Fishing spots never run out, so only the inventory stops it.
+	p := gatherer(TileWater, ResourceFishingSpot, "small_fishing_net", 1, 5)
+	p.Actions.Push(NewGatherAction(p, Point{1, 0}))
+	gatherTicks(t, p)
+	if got := p.Inventory.Count("raw_shrimps"); got != 5 {
+		t.Fatalf("caught %d shrimps before stopping, want 5", got)
+	}
+	if len(groundItems) != 0 {
+		t.Fatalf("%d items dropped on the ground", len(groundItems))
+	}
+}
+
+func TestGatherStopsWhenDepleted(t *testing.T) {
+	loadItems(t)
+	sim = NewSim(1, time.Now)
+
+	// At 99 with a rune pickaxe copper never fails, and always runs out.
+	p := gatherer(TileRock, ResourceCopper, "rune_pickaxe", 99, 10)
+	p.Actions.Push(NewGatherAction(p, Point{1, 0}))
+	if ticks := gatherTicks(t, p); ticks != 2 {
+		t.Fatalf("took %d ticks, want one attempt of 2", ticks)
+	}
+	if got := p.Inventory.Count("copper_ore"); got != 1 {
+		t.Fatalf("mined %d ore, want 1", got)
+	}
+	if p.Map.GetTile(1, 0).Type != TileGrass {
+		t.Fatal("the rock is still there")
+	}
+}
+
+func TestGatherKeepsGoing(t *testing.T) {
+	loadItems(t)
+	for seed := range int64(20) {
+		sim = NewSim(seed, time.Now)
+		groundItems = nil
+		p := gatherer(TileTree, ResourceTree, "rune_axe", 99, 20)
+		before := p.Inventory.Count("logs")
+		p.Actions.Push(NewGatherAction(p, Point{1, 0}))
+		gatherTicks(t, p)
+
+		full := p.Inventory.FreeSlots() == 0
+		felled := p.Map.GetTile(1, 0).Type == TileGrass
+		if !full && !felled {
+			t.Fatalf("seed %d: stopped with %d free slots and the tree standing", seed, p.Inventory.FreeSlots())
+		}
+		if p.Inventory.Count("logs") == before {
+			t.Fatalf("seed %d: no logs cut", seed)
+		}
+	}
+}
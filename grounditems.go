@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	// groundItemPrivateTicks is how long, in game ticks, an item dropped by
+	// or for a player stays visible to them alone.
+	groundItemPrivateTicks = 100
+	// groundItemDespawnTicks is how long an item lies on the ground in all
+	// before it disappears.
+	groundItemDespawnTicks = 300
+)
+
+// GroundItem is an item lying on a map tile. While Owner is set only the
+// owner can see or take it; after a while it turns public.
+type GroundItem struct {
+	Item         ItemSlot
+	Pos          Point
+	Owner        *Player
+	PrivateTicks int // game ticks until anyone can see it
+	TicksLeft    int // game ticks until it despawns
+}
+
+var groundItems []*GroundItem
+
+// VisibleTo reports whether p can see and take the item.
+func (g *GroundItem) VisibleTo(p *Player) bool {
+	return g.Owner == nil || g.Owner == p || g.PrivateTicks <= 0
+}
+
+// DropGroundItem puts item on the ground at pos, private to owner for a
+// while if owner isn't nil. A stackable item joins a matching pile already
+// on the tile.
+func DropGroundItem(pos Point, item ItemSlot, owner *Player) {
+	if item.Def().Stackable {
+		for _, g := range groundItems {
+			if g.Pos == pos && g.Item.ID == item.ID && g.Owner == owner {
+				g.Item.Count += item.Count
+				g.TicksLeft = groundItemDespawnTicks
+				return
+			}
+		}
+	}
+
+	g := &GroundItem{Item: item, Pos: pos, Owner: owner, TicksLeft: groundItemDespawnTicks}
+	if owner != nil {
+		g.PrivateTicks = groundItemPrivateTicks
+	}
+	groundItems = append(groundItems, g)
+}
+
+// GroundItemsAt returns the items on pos that p can see, oldest first.
+func GroundItemsAt(pos Point, p *Player) []*GroundItem {
+	var out []*GroundItem
+	for _, g := range groundItems {
+		if g.Pos == pos && g.VisibleTo(p) {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+func removeGroundItem(item *GroundItem) {
+	for i, g := range groundItems {
+		if g == item {
+			groundItems = append(groundItems[:i], groundItems[i+1:]...)
+			return
+		}
+	}
+}
+
+// updateGroundItems counts down private and despawn timers, once per game
+// tick.
+func updateGroundItems(t Tick) {
+	if !t.Game {
+		return
+	}
+
+	left := groundItems[:0]
+	for _, g := range groundItems {
+		g.PrivateTicks--
+		g.TicksLeft--
+		if g.TicksLeft > 0 {
+			left = append(left, g)
+		}
+	}
+	groundItems = left
+}
+
+// DropItem puts the item in inventory slot index on the ground under the
+// player.
+func (p *Player) DropItem(index int) {
+	item := p.Inventory.Get(index)
+	if item.Empty() {
+		return
+	}
+	p.Inventory.Set(index, ItemSlot{})
+	DropGroundItem(p.CurrentTile(), item, p)
+}
+
+// addOrDrop puts item in the player's inventory and drops whatever doesn't
+// fit on the ground at pos.
+func (p *Player) addOrDrop(item ItemSlot, pos Point) {
+	left := p.Inventory.Add(item)
+	if left == 0 {
+		return
+	}
+	item.Count = left
+	DropGroundItem(pos, item, p)
+	showMessage(fmt.Sprintf("Your inventory is full. The %s falls to the ground.", itemName(item.ID)))
+}
+
+// TryTake walks to a ground item and picks it up.
+func (p *Player) TryTake(g *GroundItem) {
+	p.Actions.Replace(NewWalkAction(p, g.Pos), NewTakeAction(p, g))
+}
+
+// TakeAction picks up a ground item from the tile the player is standing on.
+type TakeAction struct {
+	p    *Player
+	Item *GroundItem
+}
+
+func NewTakeAction(p *Player, item *GroundItem) *TakeAction {
+	return &TakeAction{p: p, Item: item}
+}
+
+func (a *TakeAction) Start() bool {
+	return a.p.CurrentTile() == a.Item.Pos
+}
+
+func (a *TakeAction) Update(t Tick) ActionStatus {
+	// Someone else may have got there first, or it may have despawned.
+	for _, g := range GroundItemsAt(a.Item.Pos, a.p) {
+		if g != a.Item {
+			continue
+		}
+		if !a.p.Inventory.CanAdd(g.Item) {
+			showMessage("You don't have enough inventory space to hold that item.")
+			return ActionDone
+		}
+		a.p.Inventory.Add(g.Item)
+		removeGroundItem(g)
+		return ActionDone
+	}
+	return ActionDone
+}
+
+func (a *TakeAction) Cancel() {}
+
+func (a *TakeAction) Complete() {}
+
+func (a *TakeAction) Label() string { return "" }
+
+// groundItemsDrawn is how many items a pile shows before it stops growing.
+const groundItemsDrawn = 3
+
+func DrawGroundItems(texture rl.Texture2D) {
+	piles := map[Point]int{}
+	for _, g := range groundItems {
+		if !g.VisibleTo(&player) {
+			continue
+		}
+		n := piles[g.Pos]
+		piles[g.Pos]++
+		if n >= groundItemsDrawn {
+			continue
+		}
+
+		// Each item in a pile sits a little up and to the left of the last.
+		pos := rl.NewVector2(float32(g.Pos.X*TileSize-n*3), float32(g.Pos.Y*TileSize-n*3))
+		if def := g.Item.Def(); def.HasSprite() {
+			rl.DrawTextureRec(texture, def.FrameRect(), pos, rl.White)
+		} else {
+			rl.DrawText(def.Name[:1], int32(pos.X+10), int32(pos.Y+8), 16, rl.DarkGray)
+		}
+		if g.Item.Count > 1 {
+			rl.DrawText(fmt.Sprintf("%d", g.Item.Count), int32(pos.X+2), int32(pos.Y+TileSize-12), 10, rl.Yellow)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestDropGroundItemMerges(t *testing.T) {
+	loadItems(t)
+	p, q := &Player{}, &Player{}
+	here, there := Point{1, 1}, Point{2, 1}
+
+	tests := []struct {
+		name  string
+		pos   Point
+		item  ItemSlot
+		owner *Player
+		piles int // on the ground afterwards
+	}{
+		{"first drop", here, ItemSlot{ID: "coins", Count: 10}, p, 1},
+		{"joins the pile", here, ItemSlot{ID: "coins", Count: 5}, p, 1},
+		{"someone else's pile", here, ItemSlot{ID: "coins", Count: 5}, q, 2},
+		{"public pile", here, ItemSlot{ID: "coins", Count: 5}, nil, 3},
+		{"another tile", there, ItemSlot{ID: "coins", Count: 5}, p, 4},
+		{"logs don't stack", here, ItemSlot{ID: "logs", Count: 1}, p, 5},
+		{"nor does a second log", here, ItemSlot{ID: "logs", Count: 1}, p, 6},
+	}
+	groundItems = nil
+	for _, tt := range tests {
+		DropGroundItem(tt.pos, tt.item, tt.owner)
+		if len(groundItems) != tt.piles {
+			t.Fatalf("%s: %d piles, want %d", tt.name, len(groundItems), tt.piles)
+		}
+	}
+	if got := groundItems[0].Item.Count; got != 15 {
+		t.Fatalf("merged pile has %d coins, want 15", got)
+	}
+}
+
+func TestGroundItemMergeResetsDespawn(t *testing.T) {
+	loadItems(t)
+	groundItems = nil
+	DropGroundItem(Point{1, 1}, ItemSlot{ID: "coins", Count: 1}, nil)
+	for range 200 {
+		updateGroundItems(Tick{Game: true})
+	}
+	DropGroundItem(Point{1, 1}, ItemSlot{ID: "coins", Count: 1}, nil)
+	if got := groundItems[0].TicksLeft; got != groundItemDespawnTicks {
+		t.Fatalf("TicksLeft = %d after adding to the pile", got)
+	}
+}
+
+func TestGroundItemTimers(t *testing.T) {
+	loadItems(t)
+	p, q := &Player{}, &Player{}
+	groundItems = nil
+	DropGroundItem(Point{1, 1}, ItemSlot{ID: "logs", Count: 1}, p)
+	DropGroundItem(Point{1, 1}, ItemSlot{ID: "oak_logs", Count: 1}, nil)
+
+	// Steps between game ticks don't count.
+	for range 1000 {
+		updateGroundItems(Tick{Dt: MoveStep})
+	}
+
+	tests := []struct {
+		ticks   int // game ticks since the drop
+		toOwner int // items the owner can see
+		toOther int // and anyone else
+	}{
+		{0, 2, 1},
+		{groundItemPrivateTicks - 1, 2, 1},
+		{groundItemPrivateTicks, 2, 2},
+		{groundItemDespawnTicks - 1, 2, 2},
+		{groundItemDespawnTicks, 0, 0},
+	}
+	elapsed := 0
+	for _, tt := range tests {
+		for ; elapsed < tt.ticks; elapsed++ {
+			updateGroundItems(Tick{Dt: MoveStep, Game: true})
+		}
+		owner, other := len(GroundItemsAt(Point{1, 1}, p)), len(GroundItemsAt(Point{1, 1}, q))
+		if owner != tt.toOwner || other != tt.toOther {
+			t.Errorf("after %d ticks: owner sees %d, other sees %d; want %d and %d", tt.ticks, owner, other, tt.toOwner, tt.toOther)
+		}
+	}
+}
+
+func TestTakeAction(t *testing.T) {
+	loadItems(t)
+	p := NewPlayer(tilePos(1, 1).X, tilePos(1, 1).Y, NewMap(3, 3), rl.Texture2D{}, rl.Texture2D{})
+	groundItems = nil
+	DropGroundItem(Point{1, 1}, ItemSlot{ID: "coins", Count: 7}, nil)
+
+	p.Actions.Push(NewTakeAction(&p, groundItems[0]))
+	p.Actions.Update(Tick{})
+	if p.Inventory.Count("coins") != 7 || len(groundItems) != 0 {
+		t.Fatalf("took %d coins, %d piles left", p.Inventory.Count("coins"), len(groundItems))
+	}
+}
@@ -1,8 +1,40 @@
 package main
 
+import "strings"
+
 func abs(n int) int {
 	if n < 0 {
 		return -n
 	}
 	return n
 }
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// adjacent reports whether b is a, or one of a's four neighbours.
+func adjacent(a, b Point) bool {
+	return abs(a.X-b.X)+abs(a.Y-b.Y) <= 1
+}
+
+func abs32(f float32) float32 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
+
+// article returns "a" or "an" to go in front of word.
+func article(word string) string {
+	if word != "" && strings.ContainsRune("aeiouAEIOU", rune(word[0])) {
+		return "an"
+	}
+	return "a"
+}
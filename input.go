@@ -0,0 +1,127 @@
+package main
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+// Input is something the player can do from the keyboard or a gamepad.
+type Input int
+
+const (
+	InputMoveUp Input = iota
+	InputMoveDown
+	InputMoveLeft
+	InputMoveRight
+	InputToggleInventory
+	InputToggleSmoothing
+	InputToggleRun
+	InputCancel
+	InputQuit
+)
+
+// Binding lists every key and gamepad button that triggers an Input.
+type Binding struct {
+	Keys    []int32
+	Buttons []int32
+}
+
+// InputMap holds the current bindings. Rebinding a control means changing
+// its entry here; nothing else checks raw keys.
+var InputMap = map[Input]Binding{
+	InputMoveUp:          {Keys: []int32{rl.KeyW, rl.KeyUp}, Buttons: []int32{rl.GamepadButtonLeftFaceUp}},
+	InputMoveDown:        {Keys: []int32{rl.KeyS, rl.KeyDown}, Buttons: []int32{rl.GamepadButtonLeftFaceDown}},
+	InputMoveLeft:        {Keys: []int32{rl.KeyA, rl.KeyLeft}, Buttons: []int32{rl.GamepadButtonLeftFaceLeft}},
+	InputMoveRight:       {Keys: []int32{rl.KeyD, rl.KeyRight}, Buttons: []int32{rl.GamepadButtonLeftFaceRight}},
+	InputToggleInventory: {Keys: []int32{rl.KeyB}, Buttons: []int32{rl.GamepadButtonMiddleRight}},
+	InputToggleSmoothing: {Keys: []int32{rl.KeyP}},
+	InputToggleRun:       {Keys: []int32{rl.KeyR}, Buttons: []int32{rl.GamepadButtonRightFaceLeft}},
+	InputCancel:          {Keys: []int32{rl.KeyEscape}, Buttons: []int32{rl.GamepadButtonRightFaceRight}},
+	InputQuit:            {Keys: []int32{rl.KeyF10}},
+}
+
+const (
+	gamepadIndex = int32(0)
+	// stickDeadzone is how far the left stick must be pushed before it
+	// counts as a direction.
+	stickDeadzone = float32(0.5)
+)
+
+func IsInputPressed(in Input) bool {
+	b := InputMap[in]
+	for _, key := range b.Keys {
+		if rl.IsKeyPressed(key) {
+			return true
+		}
+	}
+	if rl.IsGamepadAvailable(gamepadIndex) {
+		for _, button := range b.Buttons {
+			if rl.IsGamepadButtonPressed(gamepadIndex, button) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func IsInputDown(in Input) bool {
+	b := InputMap[in]
+	for _, key := range b.Keys {
+		if rl.IsKeyDown(key) {
+			return true
+		}
+	}
+	if rl.IsGamepadAvailable(gamepadIndex) {
+		for _, button := range b.Buttons {
+			if rl.IsGamepadButtonDown(gamepadIndex, button) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MovementInput returns the direction the player is holding as a one-tile
+// step, or the zero Point.
+func MovementInput() Point {
+	var x, y float32
+	if IsInputDown(InputMoveLeft) {
+		x--
+	}
+	if IsInputDown(InputMoveRight) {
+		x++
+	}
+	if IsInputDown(InputMoveUp) {
+		y--
+	}
+	if IsInputDown(InputMoveDown) {
+		y++
+	}
+
+	if x == 0 && y == 0 && rl.IsGamepadAvailable(gamepadIndex) {
+		x = rl.GetGamepadAxisMovement(gamepadIndex, rl.GamepadAxisLeftX)
+		y = rl.GetGamepadAxisMovement(gamepadIndex, rl.GamepadAxisLeftY)
+		if x > -stickDeadzone && x < stickDeadzone {
+			x = 0
+		}
+		if y > -stickDeadzone && y < stickDeadzone {
+			y = 0
+		}
+	}
+	return stepDirection(x, y)
+}
+
+// stepDirection turns a held direction into a one-tile step. Movement is
+// 4-connected, so a diagonal stick or two keys at once resolve to the
+// stronger axis, vertical on a tie.
+func stepDirection(x, y float32) Point {
+	switch {
+	case y != 0 && abs32(y) >= abs32(x):
+		if y < 0 {
+			return Point{0, -1}
+		}
+		return Point{0, 1}
+	case x < 0:
+		return Point{-1, 0}
+	case x > 0:
+		return Point{1, 0}
+	}
+	return Point{}
+}
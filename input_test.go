@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestStepDirection(t *testing.T) {
+	tests := []struct {
+		x, y float32
+		want Point
+	}{
+		{0, 0, Point{}},
+		{-1, 0, Point{-1, 0}},
+		{1, 0, Point{1, 0}},
+		{0, -1, Point{0, -1}},
+		{0, 1, Point{0, 1}},
+		// Two keys at once tie, and vertical wins.
+		{1, -1, Point{0, -1}},
+		{-1, 1, Point{0, 1}},
+		// A diagonal stick goes with the stronger axis.
+		{0.9, 0.6, Point{1, 0}},
+		{-0.6, -0.9, Point{0, -1}},
+		{-0.7, 0.5, Point{-1, 0}},
+	}
+	for _, tt := range tests {
+		if got := stepDirection(tt.x, tt.y); got != tt.want {
+			t.Errorf("stepDirection(%v, %v) = %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
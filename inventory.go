@@ -6,11 +6,19 @@ import (
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
+// ItemSlot is a stack of one kind of item. The zero value is an empty slot.
 type ItemSlot struct {
-	Name      string
-	Count     int
-	Type      string // e.g. "Weapon", "Shield", "Body"
-	FrameRect rl.Rectangle
+	ID    ItemID
+	Count int
+}
+
+// Def returns the definition of the item in the slot.
+func (s ItemSlot) Def() *ItemDef {
+	return Item(s.ID)
+}
+
+func (s ItemSlot) Empty() bool {
+	return s.ID == ""
 }
 
 type Inventory struct {
@@ -24,22 +32,73 @@ func NewInventory(texture rl.Texture2D) *Inventory {
 	}
 }
 
-func (inv *Inventory) Add(slot ItemSlot) {
-	for i := 0; i < len(inv.slots); i++ {
-		s := &inv.slots[i]
-		if s.Name == slot.Name || s.Name == "" {
-			if s.Name == "" {
-				*s = slot
-			} else {
+// Add puts slot into the inventory and returns how many of it could not fit.
+// Stackable items join an existing stack or take one free slot; anything
+// else fills a free slot per item.
+func (inv *Inventory) Add(slot ItemSlot) int {
+	if slot.Empty() || slot.Count <= 0 {
+		return 0
+	}
+
+	if slot.Def().Stackable {
+		free := -1
+		for i := range inv.slots {
+			s := &inv.slots[i]
+			if s.ID == slot.ID {
 				s.Count += slot.Count
+				return 0
 			}
-			return
+			if s.Empty() && free < 0 {
+				free = i
+			}
+		}
+		if free < 0 {
+			return slot.Count
+		}
+		inv.slots[free] = slot
+		return 0
+	}
+
+	left := slot.Count
+	for i := range inv.slots {
+		if left == 0 {
+			break
+		}
+		if inv.slots[i].Empty() {
+			inv.slots[i] = slot
+			inv.slots[i].Count = 1
+			left--
 		}
 	}
+	return left
 }
 
-func (inv *Inventory) AddByName(name string, count int, itemType string) {
-	inv.Add(ItemSlot{Name: name, Count: count, Type: itemType})
+// FreeSlots is how many inventory slots are empty.
+func (inv *Inventory) FreeSlots() int {
+	free := 0
+	for _, s := range inv.slots {
+		if s.Empty() {
+			free++
+		}
+	}
+	return free
+}
+
+// CanAdd reports whether Add would find room for all of slot.
+func (inv *Inventory) CanAdd(slot ItemSlot) bool {
+	if slot.Def().Stackable {
+		for _, s := range inv.slots {
+			if s.ID == slot.ID {
+				return true
+			}
+		}
+		return inv.FreeSlots() > 0
+	}
+	return inv.FreeSlots() >= slot.Count
+}
+
+func (inv *Inventory) AddByID(id ItemID, count int) int {
+	return inv.Add(ItemSlot{ID: id, Count: count})
 }
 
 func (inv *Inventory) Get(index int) ItemSlot {
@@ -60,23 +119,22 @@ func (inv *Inventory) Slots() [28]ItemSlot {
 	return inv.slots
 }
 
-func inferItemType(name string) string {
-	switch name {
-	case "Logs":
-		return "Material"
-	case "Ore":
-		return "Material"
-	case "Fish":
-		return "Food"
+// Count is how many of id the inventory holds across all slots.
+func (inv *Inventory) Count(id ItemID) int {
+	count := 0
+	for _, slot := range inv.slots {
+		if slot.ID == id {
+			count += slot.Count
+		}
 	}
-	return "Misc"
+	return count
 }
 
 func (inv *Inventory) HasItems(requirements []ItemSlot) bool {
 	for _, req := range requirements {
 		count := 0
 		for _, slot := range inv.slots {
-			if slot.Name == req.Name {
+			if slot.ID == req.ID {
 				count += slot.Count
 			}
 		}
@@ -92,14 +150,13 @@ func (inv *Inventory) ConsumeItems(requirements []ItemSlot) {
 		remaining := req.Count
 		for i := range inv.slots {
 			slot := &inv.slots[i]
-			if slot.Name == req.Name {
+			if slot.ID == req.ID {
 				if slot.Count > remaining {
 					slot.Count -= remaining
 					break
 				} else {
 					remaining -= slot.Count
-					slot.Name = ""
-					slot.Count = 0
+					*slot = ItemSlot{}
 				}
 			}
 		}
@@ -112,11 +169,21 @@ func drawCraftingUI(x, y int) {
 	padding := 6
 	mouse := rl.GetMousePosition()
 
-	for i, recipe := range Recipes {
+	checkQuantityClick(x, y)
+	drawQuantityPicker(x, y)
+	y += boxHeight + padding
+
+	i := 0
+	for _, recipe := range Recipes {
+		// Station recipes live in their station's panel instead.
+		if recipe.Station != StationNone {
+			continue
+		}
 		rect := rl.NewRectangle(float32(x), float32(y+i*(boxHeight+padding)), float32(boxWidth), float32(boxHeight))
+		i++
 
 		// Check if player can craft
-		canCraft := player.Inventory.HasItems(recipe.Inputs)
+		canCraft, _ := player.CanMake(recipe)
 
 		bg := rl.Gray
 		if canCraft {
@@ -126,18 +193,18 @@ func drawCraftingUI(x, y int) {
 
 			bg = rl.DarkGray
 			if rl.IsMouseButtonPressed(rl.MouseLeftButton) && canCraft {
-				player.TryCraft(recipe)
+				player.Actions.Replace(NewCraftAction(&player, recipe, makeQuantity, Point{}))
 			}
 
 			tooltip := ""
 			for _, input := range recipe.Inputs {
-				tooltip += fmt.Sprintf("%dx %s\n", input.Count, input.Name)
+				tooltip += fmt.Sprintf("%dx %s\n", input.Count, input.Def().Name)
 			}
 			rl.DrawText(tooltip, int32(mouse.X+8), int32(mouse.Y+8), 16, rl.DarkBlue)
 		}
 
 		rl.DrawRectangleRec(rect, bg)
 		rl.DrawRectangleLinesEx(rect, 1, rl.Black)
-		rl.DrawText(recipe.Name, int32(rect.X+6), int32(rect.Y+4), 16, rl.Black)
+		rl.DrawText(recipe.Output.Def().Name, int32(rect.X+6), int32(rect.Y+4), 16, rl.Black)
 	}
 }
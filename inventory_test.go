@@ -0,0 +1,82 @@
+package main
+
+import "testing"
+
+// fullInventory has a log in every slot but the last free ones.
+func fullInventory(free int) *Inventory {
+	inv := &Inventory{}
+	for i := range len(inv.slots) - free {
+		inv.Set(i, ItemSlot{ID: "logs", Count: 1})
+	}
+	return inv
+}
+
+func TestInventoryAdd(t *testing.T) {
+	loadItems(t)
+
+	tests := []struct {
+		name  string
+		inv   *Inventory
+		add   ItemSlot
+		left  int
+		free  int // FreeSlots afterwards
+		count int // of add.ID afterwards
+	}{
+		{"stackable into empty", &Inventory{}, ItemSlot{ID: "coins", Count: 100}, 0, 27, 100},
+		{"non-stackable takes a slot each", &Inventory{}, ItemSlot{ID: "logs", Count: 5}, 0, 23, 5},
+		{"non-stackable partial fit", fullInventory(3), ItemSlot{ID: "oak_logs", Count: 5}, 2, 0, 3},
+		{"stackable with no room", fullInventory(0), ItemSlot{ID: "coins", Count: 10}, 10, 0, 0},
+		{"stackable into the last slot", fullInventory(1), ItemSlot{ID: "coins", Count: 10}, 0, 0, 10},
+		{"nothing to add", &Inventory{}, ItemSlot{ID: "logs", Count: 0}, 0, 28, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if left := tt.inv.Add(tt.add); left != tt.left {
+				t.Errorf("Add left %d, want %d", left, tt.left)
+			}
+			if free := tt.inv.FreeSlots(); free != tt.free {
+				t.Errorf("FreeSlots = %d, want %d", free, tt.free)
+			}
+			if count := tt.inv.Count(tt.add.ID); count != tt.count {
+				t.Errorf("Count = %d, want %d", count, tt.count)
+			}
+		})
+	}
+}
+
+func TestInventoryStackMerge(t *testing.T) {
+	loadItems(t)
+	inv := fullInventory(1)
+	inv.Add(ItemSlot{ID: "coins", Count: 10})
+
+	// Full now, but more coins still join the stack.
+	if !inv.CanAdd(ItemSlot{ID: "coins", Count: 5}) {
+		t.Fatal("CanAdd refuses coins with a stack of them held")
+	}
+	if left := inv.Add(ItemSlot{ID: "coins", Count: 5}); left != 0 {
+		t.Fatalf("Add left %d coins", left)
+	}
+	if got := inv.Get(27); got.ID != "coins" || got.Count != 15 {
+		t.Fatalf("stack is %+v, want 15 coins", got)
+	}
+}
+
+func TestInventoryCanAdd(t *testing.T) {
+	loadItems(t)
+	tests := []struct {
+		free int
+		item ItemSlot
+		want bool
+	}{
+		{3, ItemSlot{ID: "logs", Count: 3}, true},
+		{3, ItemSlot{ID: "logs", Count: 4}, false},
+		{1, ItemSlot{ID: "coins", Count: 1000}, true},
+		{0, ItemSlot{ID: "coins", Count: 1}, false},
+		{0, ItemSlot{ID: "logs", Count: 1}, false},
+	}
+	for _, tt := range tests {
+		if got := fullInventory(tt.free).CanAdd(tt.item); got != tt.want {
+			t.Errorf("CanAdd(%v) with %d free = %v, want %v", tt.item, tt.free, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// ItemID identifies a kind of item, such as "bronze_axe". Everything else
+// about an item lives in its ItemDef.
+type ItemID string
+
+// ItemStats are the combat bonuses an item gives while equipped.
+type ItemStats struct {
+	Attack         int `json:"attack"`
+	Strength       int `json:"strength"`
+	Defence        int `json:"defence"`
+	Ranged         int `json:"ranged"`
+	RangedStrength int `json:"ranged_strength"`
+}
+
+// ItemDef is the data shared by every item of one kind.
+type ItemDef struct {
+	ID      ItemID `json:"id"`
+	Name    string `json:"name"`
+	Examine string `json:"examine"`
+	// Frame is the item's top-left corner in items.png, if it has a sprite.
+	Frame *struct {
+		X float32 `json:"x"`
+		Y float32 `json:"y"`
+	} `json:"frame"`
+	Stackable bool          `json:"stackable"`
+	Slot      EquipmentSlot `json:"slot"` // where it is worn, if anywhere
+	Stats     ItemStats     `json:"stats"`
+	Value     int           `json:"value"`
+	Weight    float32       `json:"weight"` // kilograms
+}
+
+var itemDefs = map[ItemID]*ItemDef{}
+
+// LoadItemDefs reads the item database from a JSON file.
+func LoadItemDefs(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var defs []*ItemDef
+	if err := json.Unmarshal(data, &defs); err != nil {
+		return err
+	}
+	for _, def := range defs {
+		itemDefs[def.ID] = def
+	}
+	return nil
+}
+
+// Item returns the definition for id. Unknown IDs get a bare definition
+// named after the ID so they still show up as something.
+func Item(id ItemID) *ItemDef {
+	if def, ok := itemDefs[id]; ok {
+		return def
+	}
+	return &ItemDef{ID: id, Name: string(id)}
+}
+
+// HasSprite reports whether the item has a frame in items.png.
+func (d *ItemDef) HasSprite() bool {
+	return d.Frame != nil
+}
+
+// FrameRect is the item's sprite in items.png.
+func (d *ItemDef) FrameRect() rl.Rectangle {
+	if d.Frame == nil {
+		return rl.Rectangle{}
+	}
+	return rl.NewRectangle(d.Frame.X, d.Frame.Y, TileSize, TileSize)
+}
+
+// itemName is the display name of id, lower-cased for use mid-sentence.
+func itemName(id ItemID) string {
+	return strings.ToLower(Item(id).Name)
+}
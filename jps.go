@@ -0,0 +1,181 @@
+package main
+
+import (
+	"container/heap"
+	"slices"
+)
+
+// JumpPointSearch is A* specialised for maps where every walkable tile costs
+// the same. Instead of queueing each neighbour it scans along straight lines
+// and only queues the "jump points" where a shortest path might turn, so
+// crossing an open field costs a handful of nodes instead of every tile.
+//
+// Movement is 4-connected, so among equally short paths it only considers the
+// ones that go horizontal first: horizontal scans probe up and down from
+// every tile they cross, and vertical scans only stop where an obstacle
+// beside them ends and a sideways turn becomes necessary.
+//
+// With Agility set it also takes any shortcut open at that level. Shortcut
+// ends are jump points like the goal: scans stop on them, every direction is
+// tried from them, and the shortcut itself is one more edge out of them.
+type JumpPointSearch struct {
+	Agility int
+}
+
+type jumper struct {
+	m       *Map
+	goal    Point
+	agility int
+}
+
+func (s JumpPointSearch) FindPath(start, goal Point, m *Map) []Point {
+	if start == goal {
+		return []Point{start}
+	}
+
+	j := jumper{m: m, goal: goal, agility: s.Agility}
+
+	open := make(PriorityQueue, 0)
+	heap.Init(&open)
+
+	startNode := &Node{Point: start, G: 0, H: heuristic(start, goal)}
+	startNode.F = startNode.H
+	heap.Push(&open, startNode)
+
+	costSoFar := map[Point]float64{start: 0}
+	closed := map[Point]bool{}
+
+	for open.Len() > 0 {
+		current := heap.Pop(&open).(*Node)
+
+		if current.Point == goal {
+			return expandJumps(current)
+		}
+		if closed[current.Point] {
+			continue
+		}
+		closed[current.Point] = true
+
+		push := func(next Point, cost float64, shortcut bool) {
+			if closed[next] {
+				return
+			}
+			newCost := current.G + cost
+			if oldCost, ok := costSoFar[next]; !ok || newCost < oldCost {
+				costSoFar[next] = newCost
+				h := heuristic(next, goal)
+				heap.Push(&open, &Node{
+					Point:    next,
+					G:        newCost,
+					H:        h,
+					F:        newCost + h,
+					Parent:   current,
+					Shortcut: shortcut,
+				})
+			}
+		}
+
+		for _, dir := range j.directions(current) {
+			if next, ok := j.jump(current.Point, dir); ok {
+				push(next, float64(abs(next.X-current.X)+abs(next.Y-current.Y)), false)
+			}
+		}
+		for _, s := range m.shortcutEdges(current.Point, j.agility) {
+			far := s.End()
+			if far == current.Point {
+				far = s.Start()
+			}
+			if j.passable(far) {
+				push(far, s.Cost(), true)
+			}
+		}
+	}
+	return nil
+}
+
+func (j *jumper) passable(p Point) bool {
+	return pathPassable(p, j.goal, j.m)
+}
+
+// shortcutEnd reports whether a shortcut open to the jumper starts or ends
+// at p.
+func (j *jumper) shortcutEnd(p Point) bool {
+	return len(j.m.shortcutEdges(p, j.agility)) > 0
+}
+
+// directions prunes the neighbours of a jump point down to the ones a
+// horizontal-first shortest path could continue in.
+func (j *jumper) directions(n *Node) []Point {
+	if n.Parent == nil || j.shortcutEnd(n.Point) {
+		return []Point{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+	}
+
+	dx := sign(n.X - n.Parent.X)
+	dy := sign(n.Y - n.Parent.Y)
+
+	if dx != 0 {
+		return []Point{{dx, 0}, {0, 1}, {0, -1}}
+	}
+
+	dirs := []Point{{0, dy}}
+	prev := Point{n.X, n.Y - dy}
+	for _, side := range []int{1, -1} {
+		if j.forced(prev, n.Point, side) {
+			dirs = append(dirs, Point{side, 0})
+		}
+	}
+	return dirs
+}
+
+// forced reports whether a vertical scan moving from prev to cur has just
+// passed the end of an obstacle on the given side, which is the only reason a
+// horizontal-first path would turn sideways there.
+func (j *jumper) forced(prev, cur Point, side int) bool {
+	return j.passable(Point{cur.X + side, cur.Y}) && !j.passable(Point{prev.X + side, prev.Y})
+}
+
+// jump scans from one tile in dir and returns the first jump point, if any.
+func (j *jumper) jump(from, dir Point) (Point, bool) {
+	prev := from
+	for {
+		cur := Point{prev.X + dir.X, prev.Y + dir.Y}
+		if !j.passable(cur) {
+			return Point{}, false
+		}
+		if cur == j.goal || j.shortcutEnd(cur) {
+			return cur, true
+		}
+
+		if dir.X != 0 {
+			if _, ok := j.jump(cur, Point{0, 1}); ok {
+				return cur, true
+			}
+			if _, ok := j.jump(cur, Point{0, -1}); ok {
+				return cur, true
+			}
+		} else if j.forced(prev, cur, 1) || j.forced(prev, cur, -1) {
+			return cur, true
+		}
+
+		prev = cur
+	}
+}
+
+// expandJumps fills in the straight runs between the jump points leading to
+// end so callers get the same tile-by-tile path A* would return. Shortcuts
+// stay single steps, as they do in A*.
+func expandJumps(end *Node) []Point {
+	var path []Point
+	for n := end; n != nil; n = n.Parent {
+		path = append(path, n.Point)
+		if n.Parent == nil || n.Shortcut {
+			continue
+		}
+		dx, dy := sign(n.Parent.X-n.X), sign(n.Parent.Y-n.Y)
+		for p := (Point{n.X + dx, n.Y + dy}); p != n.Parent.Point; p = (Point{p.X + dx, p.Y + dy}) {
+			path = append(path, p)
+		}
+	}
+	slices.Reverse(path)
+	return path
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// randomMap builds a w by h map with walls and occupied tiles scattered over
+// it at the given densities.
+func randomMap(rng *rand.Rand, w, h int, walls, occupied float64) *Map {
+	m := NewMap(w, h)
+	for y := range h {
+		for x := range w {
+			switch r := rng.Float64(); {
+			case r < walls:
+				m.Tiles[y][x] = Tile{Type: TileWall}
+			case r < walls+occupied:
+				m.Occupancy.Add(Point{x, y})
+			}
+		}
+	}
+	return m
+}
+
+func randomPoint(rng *rand.Rand, m *Map) Point {
+	return Point{rng.Intn(m.Width), rng.Intn(m.Height)}
+}
+
+// checkPath fails t unless path runs from start to goal in 4-adjacent steps,
+// or shortcuts open at agility, over tiles a planner may use. It returns what
+// the path costs.
+func checkPath(t *testing.T, name string, path []Point, start, goal Point, m *Map, agility int) float64 {
+	t.Helper()
+	if path[0] != start || path[len(path)-1] != goal {
+		t.Fatalf("%s: path %v doesn't run from %v to %v", name, path, start, goal)
+	}
+	cost := 0.0
+	for i := 1; i < len(path); i++ {
+		a, b := path[i-1], path[i]
+		if abs(a.X-b.X)+abs(a.Y-b.Y) == 1 {
+			cost += m.GetTile(b.X, b.Y).MoveCost()
+		} else if s, _ := m.ShortcutFrom(a, b, agility); s != nil {
+			cost += s.Cost()
+		} else {
+			t.Fatalf("%s: step %v -> %v is not 4-adjacent", name, a, b)
+		}
+		if !pathPassable(b, goal, m) {
+			t.Fatalf("%s: step onto %v, which is not passable", name, b)
+		}
+	}
+	return cost
+}
+
+func TestJumpPointSearchMatchesAStar(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := range 2000 {
+		m := randomMap(rng, 5+rng.Intn(20), 5+rng.Intn(20), 0.3*rng.Float64(), 0.1*rng.Float64())
+		start, goal := randomPoint(rng, m), randomPoint(rng, m)
+		if !m.CanEnter(start) {
+			continue
+		}
+
+		want := AStar{}.FindPath(start, goal, m)
+		got := JumpPointSearch{}.FindPath(start, goal, m)
+		if (want == nil) != (got == nil) {
+			t.Fatalf("map %d, %v -> %v: A* found %v, JPS found %v", i, start, goal, want, got)
+		}
+		if want == nil {
+			continue
+		}
+		if len(got) != len(want) {
+			t.Fatalf("map %d, %v -> %v: JPS path has %d tiles, A* has %d", i, start, goal, len(got), len(want))
+		}
+		checkPath(t, "A*", want, start, goal, m, 0)
+		checkPath(t, "JPS", got, start, goal, m, 0)
+	}
+}
+
+func TestJumpPointSearchTakesShortcuts(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for i := range 5000 {
+		m := randomMap(rng, 5+rng.Intn(20), 5+rng.Intn(20), 0.3*rng.Float64(), 0.1*rng.Float64())
+		for range 1 + rng.Intn(6) {
+			route := []Point{randomPoint(rng, m)}
+			for range 1 + rng.Intn(4) {
+				route = append(route, randomPoint(rng, m))
+			}
+			m.Shortcuts = append(m.Shortcuts, Shortcut{Name: "Shortcut", Route: route, Level: 1 + rng.Intn(10)})
+		}
+		agility := rng.Intn(12)
+		start, goal := randomPoint(rng, m), randomPoint(rng, m)
+		if !m.CanEnter(start) {
+			continue
+		}
+
+		want := AStar{Agility: agility}.FindPath(start, goal, m)
+		got := JumpPointSearch{Agility: agility}.FindPath(start, goal, m)
+		if (want == nil) != (got == nil) {
+			t.Fatalf("map %d, %v -> %v: A* found %v, JPS found %v", i, start, goal, want, got)
+		}
+		if want == nil {
+			continue
+		}
+		wantCost := checkPath(t, "A*", want, start, goal, m, agility)
+		gotCost := checkPath(t, "JPS", got, start, goal, m, agility)
+		if gotCost != wantCost {
+			t.Fatalf("map %d, %v -> %v: JPS path %v costs %v, A* path %v costs %v", i, start, goal, got, gotCost, want, wantCost)
+		}
+	}
+}
+
+func TestPathfinderForUsesJPSWithShortcuts(t *testing.T) {
+	m := NewMap(20, 15)
+	m.placeShortcuts()
+	if _, ok := PathfinderFor(m, 1).(JumpPointSearch); !ok {
+		t.Fatalf("PathfinderFor picked %T on a uniform map", PathfinderFor(m, 1))
+	}
+}
@@ -2,155 +2,146 @@ package main
 
 import (
 	"fmt"
-	"math/rand"
+	"os"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
 var (
-	player        Player
-	gameMap       *Map
-	showInventory bool
-	Recipes       = []Recipe{
-		{
-			Name: "Sword",
-			Inputs: []ItemSlot{
-				{Name: "Ore", Count: 2},
-				{Name: "Logs", Count: 1},
-			},
-			Output: ItemSlot{Name: "Sword", Count: 1, Type: "Weapon", FrameRect: rl.NewRectangle(0, 0, 32, 32)},
-		},
-	}
-	enemies          []Enemy
-	inCombat         bool
-	currentEnemy     *Enemy
-	playerTurn       bool
-	combatTimer      float32
-	combatInterval   = float32(1.0) // seconds per turn
-	lootMessage      string
-	lootMessageTimer float32
+	player         Player
+	gameMap        *Map
+	showInventory  bool
+	enemies        []Enemy
+	inCombat       bool
+	currentEnemy   *Enemy
+	playerTurn     bool
+	combatTimer    int // game ticks until the next turn
+	combatInterval = 2 // game ticks per turn
+	message        string
+	messageTimer   float32
+	chaseField     *FlowField
+	fleeField      *FlowField
+	clock          Clock
+	quit           bool // set by InputQuit to end the main loop
 )
 
+// showMessage puts a line of game text above the status bar for a couple of
+// seconds.
+func showMessage(text string) {
+	fmt.Println(text)
+	message = text
+	messageTimer = 2.0
+}
+
 func Update() {
 
-	if lootMessageTimer > 0 {
-		lootMessageTimer -= rl.GetFrameTime()
+	if messageTimer > 0 {
+		messageTimer -= rl.GetFrameTime()
 	}
 
-	clickedIndex, clickedUI := -1, false
-
-	if showInventory {
-		clickedIndex, clickedUI = player.CheckInventoryClick(10, 10)
+	// An open menu takes the click before anything underneath it.
+	if !updateContextMenu() {
+		handleClicks()
+	}
 
-		if clickedIndex >= 0 {
-			item := player.Inventory.Get(clickedIndex)
-			slot := mapItemTypeToSlot(item.Type)
+	// Keys go to a text box while one has focus.
+	if !typing() {
+		if dir := MovementInput(); dir != (Point{}) {
+			player.Step(dir)
+		}
 
-			if slot != "" {
-				swapped := player.Equipment.Unequip(slot)
+		if IsInputPressed(InputToggleInventory) {
+			showInventory = !showInventory
+		}
 
-				remaining := player.Equipment.Equip(slot, item)
-				player.Inventory.Set(clickedIndex, remaining) // either empty or rest of stack
+		if IsInputPressed(InputToggleSmoothing) {
+			player.SmoothPaths = !player.SmoothPaths
+		}
 
-				if swapped.Name != "" {
-					player.Inventory.Add(swapped)
-				}
-			}
+		if IsInputPressed(InputToggleRun) {
+			player.ToggleRun()
 		}
 
-		if slot, eqClicked := player.CheckEquipmentClick(400, 10); eqClicked {
-			clickedUI = true // <- mark UI was clicked
-			item := player.Equipment.Unequip(slot)
-			if item.Name != "" {
-				player.Inventory.Add(item)
-			}
+		if IsInputPressed(InputQuit) {
+			quit = true
 		}
 	}
 
-	// Only click map if not interacting with UI
-	if !clickedUI && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
-		mouse := rl.GetMousePosition()
-		tileX := int(mouse.X) / TileSize
-		tileY := int(mouse.Y) / TileSize
-
-		tile := gameMap.GetTile(tileX, tileY)
-		if tile != nil && tile.IsGatherable() {
-			player.TryGatherAt(tileX, tileY)
-		} else {
-			player.MoveToTile(tileX, tileY)
-		}
+	for n := clock.Advance(rl.GetFrameTime()); n > 0; n-- {
+		Simulate(clock.Next())
+	}
+}
+
+// handleClicks passes a left-click to the UI under the mouse, or failing
+// that to the map.
+func handleClicks() {
+	clickedUI := false
+
+	if showInventory {
+		clickedUI = player.CheckInventoryClick(inventoryX, inventoryY) ||
+			player.GetHoveredEquipmentSlot(equipmentX, equipmentY) != ""
 	}
 
-	if rl.IsKeyPressed(rl.KeyB) {
-		showInventory = !showInventory
+	if ref, clicked := updateDrag(); clicked {
+		runDefault(slotOptions(ref))
 	}
 
-	if !inCombat {
-		for i := range enemies {
-			if rl.Vector2Distance(player.Pos, enemies[i].Pos) < float32(TileSize) {
-				inCombat = true
-				currentEnemy = &enemies[i]
-				playerTurn = true
-				combatTimer = combatInterval
-				break
-			}
-		}
+	if player.CheckRunOrbClick() {
+		clickedUI = true
 	}
 
-	if inCombat && currentEnemy != nil {
-		combatTimer -= rl.GetFrameTime()
-		if combatTimer <= 0 {
-			if playerTurn {
-				currentEnemy.Health -= 10
-				fmt.Println("Player hits", currentEnemy.Name, "for 10 damage")
-
-				if currentEnemy.Health <= 0 {
-					fmt.Println(currentEnemy.Name, "is defeated!")
-
-					for _, loot := range currentEnemy.LootTable {
-						if rand.Float32() <= loot.Chance {
-							player.Inventory.Add(loot.Item)
-							fmt.Printf("Looted: %s x%d\n", loot.Item.Name, loot.Item.Count)
-							lootMessage = fmt.Sprintf("You looted %s x%d", loot.Item.Name, loot.Item.Count)
-							lootMessageTimer = 2.0
-						}
-					}
-
-					alive := enemies[:0]
-					for _, e := range enemies {
-						if e.Health > 0 {
-							alive = append(alive, e)
-						}
-					}
-					enemies = alive
-
-					inCombat = false
-					currentEnemy = nil
-					return
-				}
-			} else {
-				player.Health -= 5
-				fmt.Println(currentEnemy.Name, "hits Player for 5 damage")
-
-				if player.Health <= 0 {
-					fmt.Println("You died!")
-					inCombat = false
-					currentEnemy = nil
-					return
-				}
-			}
-			playerTurn = !playerTurn
-			combatTimer = combatInterval
+	updateQuantityInput()
+	if player.CheckStationClick() {
+		clickedUI = true
+	}
+
+	updateBankSearch()
+	if player.CheckBankClick() {
+		clickedUI = true
+	}
+
+	// Only click map if not interacting with UI
+	if !clickedUI && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		mouse := rl.GetMousePosition()
+		runDefault(tileOptions(Point{int(mouse.X) / TileSize, int(mouse.Y) / TileSize}))
+	}
+}
+
+// Simulate advances the world by one fixed step. Chance rolls and the time
+// of day come from sim.
+func Simulate(t Tick) {
+	// Flow fields only rebuild when the player changes tile or the map
+	// changes, so every enemy shares the same per-step cost.
+	playerTile := player.CurrentTile()
+	chaseField.Update(playerTile)
+	fleeField.Update(playerTile)
+
+	gameMap.Occupancy.Clear()
+	for i := range enemies {
+		gameMap.Occupancy.Add(enemies[i].Tile())
+		if enemies[i].moving {
+			gameMap.Occupancy.Add(enemies[i].next)
 		}
 	}
 
-	if inCombat && currentEnemy != nil && rl.Vector2Distance(player.Pos, currentEnemy.Pos) > float32(TileSize*2) {
-		fmt.Println("You escaped combat.")
-		inCombat = false
-		currentEnemy = nil
+	for i := range enemies {
+		if inCombat && currentEnemy == &enemies[i] {
+			enemies[i].PrevPos = enemies[i].Pos
+			continue
+		}
+		field := chaseField
+		if enemies[i].Cowardly {
+			field = fleeField
+		}
+		enemies[i].Update(t, field)
 	}
 
-	player.Update(rl.GetFrameTime())
+	gameMap.UpdateFishingSpots(t)
+	updateFires(t)
+	updateGroundItems(t)
+	updateFarming(t)
+	updateCombat(t)
+	player.Update(t)
 }
 
 func Draw(tilemap rl.Texture2D) {
@@ -158,18 +149,19 @@ func Draw(tilemap rl.Texture2D) {
 	rl.ClearBackground(rl.RayWhite)
 
 	gameMap.Draw(tilemap)
-	player.Draw()
+	DrawGroundItems(player.Inventory.ItemsTexture)
+	player.Draw(clock.Alpha())
 
 	if showInventory {
-		player.DrawInventory(10, 10)
-		player.DrawEquipment(400, 10)
+		player.DrawInventory(inventoryX, inventoryY)
+		player.DrawEquipment(equipmentX, equipmentY)
 
-		hovered := player.GetHoveredInventoryIndex(10, 10)
-		if hovered >= 0 {
+		hovered := player.GetHoveredInventoryIndex(inventoryX, inventoryY)
+		if hovered >= 0 && drag == nil {
 			item := player.Inventory.Get(hovered)
-			if item.Name != "" {
+			if !item.Empty() {
 				mouse := rl.GetMousePosition()
-				text := item.Name
+				text := item.Def().Name
 				textWidth := rl.MeasureText(text, 16)
 				padding := 4
 				rect := rl.NewRectangle(mouse.X, mouse.Y-24, float32(textWidth+int32(padding)*2), 20)
@@ -180,12 +172,12 @@ func Draw(tilemap rl.Texture2D) {
 		}
 
 		// After inventory tooltip
-		hoveredEq := player.GetHoveredEquipmentSlot(400, 10)
-		if hoveredEq != "" {
+		hoveredEq := player.GetHoveredEquipmentSlot(equipmentX, equipmentY)
+		if hoveredEq != "" && drag == nil {
 			item := player.Equipment.Slots[hoveredEq]
-			if item.Name != "" {
+			if !item.Empty() {
 				mouse := rl.GetMousePosition()
-				text := item.Name
+				text := item.Def().Name
 				textWidth := rl.MeasureText(text, 16)
 				padding := 4
 				rect := rl.NewRectangle(mouse.X, mouse.Y-24, float32(textWidth+int32(padding)*2), 20)
@@ -195,15 +187,22 @@ func Draw(tilemap rl.Texture2D) {
 			}
 		}
 
-		drawCraftingUI(600, 10) // adjust x/y as needed
+		player.DrawSkills(skillsX, skillsY)
+		switch {
+		case bankOpen:
+			player.DrawBank()
+		case openStation == StationNone:
+			drawCraftingUI(600, 10) // adjust x/y as needed
+		}
+		drawDrag()
 	}
 
-	if player.Gathering {
-		rl.DrawText(player.GatherLabel, 10, ScreenHeight-30, 20, rl.Black)
+	if label := player.Actions.Label(); label != "" {
+		rl.DrawText(label, 10, ScreenHeight-30, 20, rl.Black)
 	}
 
 	for _, enemy := range enemies {
-		enemy.Draw()
+		enemy.Draw(clock.Alpha())
 	}
 
 	if inCombat && currentEnemy != nil {
@@ -216,18 +215,33 @@ func Draw(tilemap rl.Texture2D) {
 		rl.DrawText(turn, 10, ScreenHeight-40, 20, rl.DarkGray)
 	}
 
-	rl.DrawText(fmt.Sprintf("Player HP: %d", player.Health), 10, 10, 20, rl.Black)
+	rl.DrawText(fmt.Sprintf("Player HP: %d", player.Health), 10, 30, 20, rl.Black)
+	player.DrawRunOrb()
+	player.DrawStationUI()
 
-	if lootMessageTimer > 0 {
-		rl.DrawText(lootMessage, 10, ScreenHeight-90, 20, rl.DarkGreen)
+	if messageTimer > 0 {
+		rl.DrawText(message, 10, ScreenHeight-90, 20, rl.DarkGreen)
 	}
 
+	drawHoverAction()
+	drawContextMenu()
+
 	rl.EndDrawing()
 }
 
 func main() {
+	// Without item definitions every item is a bare name that can't be
+	// stacked, worn or drawn, so don't start at all.
+	if err := LoadItemDefs("assets/items.json"); err != nil {
+		fmt.Println("Failed to load item definitions:", err)
+		os.Exit(1)
+	}
+
 	rl.InitWindow(ScreenWidth, ScreenHeight, "RuneClone")
 	rl.SetTargetFPS(60)
+	// Escape cancels text boxes rather than closing the window; quitting
+	// goes through InputQuit.
+	rl.SetExitKey(0)
 
 	tilemap := rl.LoadTexture("assets/tiles.png")
 	defer rl.UnloadTexture(tilemap)
@@ -243,6 +257,12 @@ func main() {
 
 	gameMap = NewMap(20, 15)
 	gameMap.Generate(0.1, 0.05, 0.05)
+	if err := LoadFarming(gameMap); err != nil {
+		fmt.Println("Failed to load farming patches:", err)
+	}
+
+	chaseField = NewFlowField(gameMap, false)
+	fleeField = NewFlowField(gameMap, true)
 
 	player = NewPlayer(
 		float32((SpawnX+SpawnWidth/2)*TileSize),
@@ -251,40 +271,47 @@ func main() {
 		characterTilemap,
 		itemTexture,
 	)
-
-	enemies = append(enemies, Enemy{
-		Pos:       rl.NewVector2(100, 100),
-		Health:    50,
-		MaxHealth: 50,
-		Name:      "Slime",
-		Texture:   enemyTex,
-		Frame:     rl.NewRectangle(0, 64, TileSize, TileSize),
+	if err := LoadBank(player.Bank); err != nil {
+		fmt.Println("Failed to load bank:", err)
+	}
+	for _, tool := range []ItemID{
+		"bronze_axe", "bronze_pickaxe", "small_fishing_net", "tinderbox", "hammer",
+		"knife", "rake", "seed_dibber", "spade", "watering_can",
+	} {
+		player.Inventory.AddByID(tool, 1)
+	}
+	player.Inventory.AddByID("potato_seed", 6)
+	player.Inventory.AddByID("compost", 1)
+
+	spawnEnemy(Enemy{
+		Pos:        rl.NewVector2(100, 100),
+		Health:     50,
+		MaxHealth:  50,
+		Name:       "Slime",
+		Examine:    "A quivering lump of goo.",
+		Texture:    enemyTex,
+		Frame:      rl.NewRectangle(0, 64, TileSize, TileSize),
+		Speed:      60,
+		AggroRange: 6,
 		LootTable: []LootEntry{
-			{
-				Item: ItemSlot{
-					Name:      "Club",
-					Count:     1,
-					Type:      "Weapon",
-					FrameRect: rl.NewRectangle(0, 256, TileSize, TileSize),
-				},
-				Chance: 0.3, // 30% chance
-			},
-			{
-				Item: ItemSlot{
-					Name:      "Coins",
-					Count:     5,
-					Type:      "Misc",
-					FrameRect: rl.NewRectangle(32, 768, TileSize, TileSize),
-				},
-				Chance: 0.7, // 70% chance
-			},
+			{Item: ItemSlot{ID: "club", Count: 1}, Chance: 0.3},
+			{Item: ItemSlot{ID: "coins", Count: 5}, Chance: 0.7},
+			{Item: ItemSlot{ID: "feather", Count: 15}, Chance: 0.5},
+			{Item: ItemSlot{ID: "bow_string", Count: 1}, Chance: 0.25},
 		},
 	})
 
-	for !rl.WindowShouldClose() {
+	for !rl.WindowShouldClose() && !quit {
 		Update()
 		Draw(tilemap)
 	}
 
+	if err := SaveFarming(gameMap); err != nil {
+		fmt.Println("Failed to save farming patches:", err)
+	}
+	if err := SaveBank(player.Bank); err != nil {
+		fmt.Println("Failed to save bank:", err)
+	}
+
 	rl.CloseWindow()
 }
@@ -1,16 +1,24 @@
 package main
 
-import (
-	"math/rand"
-	"time"
-
-	rl "github.com/gen2brain/raylib-go/raylib"
-)
+import rl "github.com/gen2brain/raylib-go/raylib"
 
 type Map struct {
 	Width  int
 	Height int
 	Tiles  [][]Tile
+
+	// Version is bumped on every terrain change so cached data derived from
+	// the map (such as flow fields) knows when to rebuild.
+	Version int
+
+	// Occupancy marks tiles taken by entities. FindPath routes around them.
+	Occupancy *Occupancy
+
+	// Shortcuts are agility obstacles the player can cross with the level.
+	Shortcuts []Shortcut
+
+	uniform        bool
+	uniformVersion int
 }
 
 func NewMap(width, height int) *Map {
@@ -22,10 +30,39 @@ func NewMap(width, height int) *Map {
 		}
 	}
 	return &Map{
-		Width:  width,
-		Height: height,
-		Tiles:  tiles,
+		Width:          width,
+		Height:         height,
+		Tiles:          tiles,
+		Occupancy:      NewOccupancy(width, height),
+		uniformVersion: -1,
+	}
+}
+
+// UniformCost reports whether every walkable tile has the same MoveCost. The
+// answer is cached until the map changes.
+func (m *Map) UniformCost() bool {
+	if m.uniformVersion == m.Version {
+		return m.uniform
+	}
+
+	m.uniform = true
+	cost := -1.0
+	for y := 0; y < m.Height && m.uniform; y++ {
+		for x := 0; x < m.Width; x++ {
+			tile := m.Tiles[y][x]
+			if !tile.IsWalkable() {
+				continue
+			}
+			if cost < 0 {
+				cost = tile.MoveCost()
+			} else if tile.MoveCost() != cost {
+				m.uniform = false
+				break
+			}
+		}
 	}
+	m.uniformVersion = m.Version
+	return m.uniform
 }
 
 func (m *Map) GetTile(x, y int) *Tile {
@@ -35,9 +72,25 @@ func (m *Map) GetTile(x, y int) *Tile {
 	return &m.Tiles[y][x]
 }
 
+// CanEnter reports whether an entity could step onto p right now: the terrain
+// must be walkable and no other entity may be standing there.
+func (m *Map) CanEnter(p Point) bool {
+	tile := m.GetTile(p.X, p.Y)
+	if tile == nil || !tile.IsWalkable() {
+		return false
+	}
+	return !m.Occupancy.IsOccupied(p)
+}
+
 func (m *Map) SetTile(x, y int, tileType int) {
-	if tile := m.GetTile(x, y); tile != nil {
+	if tile := m.GetTile(x, y); tile != nil && tile.Type != tileType {
 		tile.Type = tileType
+		tile.Resource = defaultResource[tileType]
+		tile.Patch = nil
+		if tileType == TileFarmPatch {
+			tile.Patch = &Patch{}
+		}
+		m.Version++
 	}
 }
 
@@ -47,11 +100,10 @@ func (m *Map) Draw(texture rl.Texture2D) {
 			m.Tiles[y][x].Draw(texture, int32(x), int32(y))
 		}
 	}
+	m.drawShortcuts()
 }
 
 func (m *Map) Generate(treeChance, rockChance, waterChance float64) {
-	rand.Seed(time.Now().UnixNano())
-
 	for y := 0; y < m.Height; y++ {
 		for x := 0; x < m.Width; x++ {
 			// Skip spawn zone
@@ -62,17 +114,50 @@ func (m *Map) Generate(treeChance, rockChance, waterChance float64) {
 			}
 
 			// Randomly assign tile type
-			r := rand.Float64()
+			r := sim.Rand.Float64()
 			switch {
 			case r < treeChance:
-				m.Tiles[y][x] = Tile{Type: TileTree}
+				m.Tiles[y][x] = Tile{Type: TileTree, Resource: randomResource(TileTree)}
 			case r < treeChance+rockChance:
-				m.Tiles[y][x] = Tile{Type: TileRock}
+				m.Tiles[y][x] = Tile{Type: TileRock, Resource: randomResource(TileRock)}
 			case r < treeChance+rockChance+waterChance:
 				m.Tiles[y][x] = Tile{Type: TileWater}
+				if sim.Rand.Float64() < fishingSpotChance {
+					m.Tiles[y][x].Resource = ResourceFishingSpot
+				}
 			default:
 				m.Tiles[y][x] = Tile{Type: TileGrass}
 			}
 		}
 	}
+
+	// A range just outside the spawn, so there is always somewhere to cook.
+	if SpawnX+SpawnWidth < m.Width && SpawnY+1 < m.Height {
+		m.Tiles[SpawnY+1][SpawnX+SpawnWidth] = Tile{Type: TileRange}
+	}
+	// And a furnace and anvil along the bottom edge for smithing.
+	if SpawnX+2 < m.Width && SpawnY+SpawnHeight < m.Height {
+		m.Tiles[SpawnY+SpawnHeight][SpawnX+1] = Tile{Type: TileFurnace}
+		m.Tiles[SpawnY+SpawnHeight][SpawnX+2] = Tile{Type: TileAnvil}
+	}
+	// A bank booth on the top edge, above the spawn.
+	if SpawnY > 0 && SpawnX+1 < m.Width {
+		m.Tiles[SpawnY-1][SpawnX+1] = Tile{Type: TileBankBooth}
+	}
+	m.placeShortcuts()
+
+	// Farming patches always sit in the same place so saved crops find
+	// their patch again on the next run.
+	if SpawnX+SpawnWidth < m.Width && SpawnY+SpawnHeight+2 < m.Height {
+		for x := SpawnX; x < SpawnX+SpawnWidth; x++ {
+			m.Tiles[SpawnY+SpawnHeight+2][x] = Tile{Type: TileFarmPatch, Patch: &Patch{}}
+		}
+		// Keep the row above clear, and the tile beside the furnace that
+		// leads down to it from the spawn, so every patch can be reached.
+		for x := SpawnX; x <= SpawnX+SpawnWidth; x++ {
+			m.Tiles[SpawnY+SpawnHeight+1][x] = Tile{Type: TileGrass}
+		}
+		m.Tiles[SpawnY+SpawnHeight][SpawnX] = Tile{Type: TileGrass}
+	}
+	m.Version++
 }
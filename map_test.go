@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// reachable is every tile that can be walked to from start.
+func reachable(m *Map, start Point) map[Point]bool {
+	seen := map[Point]bool{start: true}
+	queue := []Point{start}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for _, n := range neighbors(p) {
+			if tile := m.GetTile(n.X, n.Y); tile != nil && tile.IsWalkable() && !seen[n] {
+				seen[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	return seen
+}
+
+func TestGeneratedPatchesReachable(t *testing.T) {
+	for seed := range int64(500) {
+		sim = NewSim(seed, time.Now)
+		m := NewMap(20, 15)
+		// Plenty of obstacles, to give them every chance to wall a patch in.
+		m.Generate(0.3, 0.2, 0.2)
+
+		walk := reachable(m, Point{SpawnX, SpawnY})
+		for y := range m.Tiles {
+			for x := range m.Tiles[y] {
+				if m.Tiles[y][x].Patch == nil {
+					continue
+				}
+				ok := false
+				for _, n := range neighbors(Point{x, y}) {
+					ok = ok || walk[n]
+				}
+				if !ok {
+					t.Fatalf("seed %d: patch at %d,%d can't be reached from the spawn", seed, x, y)
+				}
+			}
+		}
+	}
+}
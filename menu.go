@@ -0,0 +1,351 @@
+package main
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// MenuOption is one line of a right-click menu, such as "Chop down" "Oak
+// tree". Left-clicking something does its first option.
+type MenuOption struct {
+	Verb   string
+	Target string
+	Do     func()
+}
+
+// ContextMenu is an open right-click menu.
+type ContextMenu struct {
+	Pos     rl.Vector2
+	Options []MenuOption
+}
+
+var contextMenu *ContextMenu
+
+// usingItem is the inventory slot picked with "Use", waiting for something
+// to use it on, or -1. usingID is the item that was in it, so that whatever
+// takes its place after a drag, drop or craft isn't used instead.
+var (
+	usingItem = -1
+	usingID   ItemID
+)
+
+// pickedItem returns usingItem, putting it down first if the item picked has
+// since left its slot.
+func pickedItem() int {
+	if usingItem >= 0 && player.Inventory.Get(usingItem).ID != usingID {
+		usingItem = -1
+	}
+	return usingItem
+}
+
+const (
+	menuRowHeight = 20
+	menuPadding   = 6
+	menuFontSize  = 16
+	menuTitle     = "Choose Option"
+
+	// The hover line sits top-left, above the HP line and the panels.
+	hoverActionX, hoverActionY = 4, 4
+)
+
+func examineOption(name, text string) MenuOption {
+	return MenuOption{"Examine", name, func() { showMessage(text) }}
+}
+
+// useOption uses the item picked with "Use" on target. A menu can stay open
+// while the inventory changes, so the slot is checked again before do runs.
+func useOption(target string, do func(used int)) MenuOption {
+	used, id := usingItem, usingID
+	return MenuOption{"Use", Item(id).Name + " -> " + target, func() {
+		if player.Inventory.Get(used).ID == id {
+			do(used)
+		}
+	}}
+}
+
+// itemOptions lists what can be done with the item in inventory slot index.
+func itemOptions(index int) []MenuOption {
+	item := player.Inventory.Get(index)
+	if item.Empty() {
+		return nil
+	}
+	def := item.Def()
+
+	if pickedItem() >= 0 {
+		return []MenuOption{useOption(def.Name, func(used int) {
+			if player.Inventory.Get(index).ID == item.ID {
+				player.UseItemOnItem(used, index)
+			}
+		})}
+	}
+	if bankOpen {
+		return depositOptions(index)
+	}
+
+	var opts []MenuOption
+	if foodHeals(item.ID) > 0 {
+		opts = append(opts, MenuOption{"Eat", def.Name, func() { player.Eat(index) }})
+	}
+	if def.Slot != "" {
+		opts = append(opts, MenuOption{"Equip", def.Name, func() { player.EquipFrom(index) }})
+	}
+	if burnableByLogs(item.ID) != nil {
+		opts = append(opts, MenuOption{"Light", def.Name, func() { player.TryLightFire(index) }})
+	}
+	opts = append(opts,
+		MenuOption{"Use", def.Name, func() { usingItem, usingID = index, item.ID }},
+		MenuOption{"Drop", def.Name, func() { player.DropItem(index) }},
+	)
+	if def.Examine != "" {
+		opts = append(opts, examineOption(def.Name, def.Examine))
+	}
+	return opts
+}
+
+// equipmentOptions lists what can be done with whatever is worn in slot.
+func equipmentOptions(slot EquipmentSlot) []MenuOption {
+	item := player.Equipment.Slots[slot]
+	if item.Empty() {
+		return nil
+	}
+	def := item.Def()
+
+	opts := []MenuOption{{"Remove", def.Name, func() { player.UnequipTo(slot, -1) }}}
+	if def.Examine != "" {
+		opts = append(opts, examineOption(def.Name, def.Examine))
+	}
+	return opts
+}
+
+// tileName is what a tile is called, going by its resource if it has one.
+func tileName(t *Tile) string {
+	if res, ok := resources[t.Resource]; ok {
+		return res.Name
+	}
+	return tileDefs[t.Type].Name
+}
+
+func tileExamine(t *Tile) string {
+	if res, ok := resources[t.Resource]; ok {
+		return res.Examine
+	}
+	return tileDefs[t.Type].Examine
+}
+
+// tileOptions lists what can be done with a map tile and anything on it.
+func tileOptions(pos Point) []MenuOption {
+	enemy := enemyAt(pos)
+	tile := gameMap.GetTile(pos.X, pos.Y)
+	walk := MenuOption{"Walk here", "", func() { player.Actions.Replace(NewWalkAction(&player, pos)) }}
+
+	if pickedItem() >= 0 {
+		switch {
+		case enemy != nil:
+			id := enemy.ID
+			return []MenuOption{useOption(enemy.Name, func(used int) {
+				if e := enemyByID(id); e != nil {
+					player.UseItemOnEnemy(used, e)
+				}
+			}), walk}
+		case tile != nil && !tile.IsWalkable():
+			return []MenuOption{useOption(tileName(tile), func(used int) { player.UseItemOnTile(used, pos) }), walk}
+		}
+		return []MenuOption{walk}
+	}
+
+	var opts []MenuOption
+	if enemy != nil {
+		// Menus outlive the frame, so look the enemy up again by ID.
+		id := enemy.ID
+		opts = append(opts, MenuOption{"Attack", enemy.Name, func() {
+			if e := enemyByID(id); e != nil {
+				player.TryAttack(e)
+			}
+		}})
+	}
+	for _, g := range GroundItemsAt(pos, &player) {
+		opts = append(opts, MenuOption{"Take", g.Item.Def().Name, func() { player.TryTake(g) }})
+	}
+	if tile != nil {
+		name := tileName(tile)
+		if s := gameMap.ShortcutAt(pos); s != nil && !tile.IsWalkable() {
+			opts = append(opts, MenuOption{shortcutOptions[s.Kind], s.Name, func() { player.TryShortcut(s) }})
+		}
+		switch {
+		case tile.IsGatherable():
+			opts = append(opts, MenuOption{gatherSettings[tile.Type].Option, name, func() { player.TryGatherAt(pos.X, pos.Y) }})
+		case tile.IsCookingSource():
+			opts = append(opts, MenuOption{"Cook-at", name, func() { player.TryCookAt(pos.X, pos.Y) }})
+		case tile.IsPatch():
+			opts = append(opts, MenuOption{"Tend", name, func() { player.TendPatchAt(pos.X, pos.Y) }})
+		case tile.IsStation():
+			opts = append(opts, MenuOption{"Use", name, func() { player.OpenStation(pos.X, pos.Y) }})
+		case tile.IsBank():
+			opts = append(opts, MenuOption{"Bank", name, func() { player.OpenBank(pos.X, pos.Y) }})
+		}
+	}
+	opts = append(opts, walk)
+
+	if enemy != nil && enemy.Examine != "" {
+		opts = append(opts, examineOption(enemy.Name, enemy.Examine))
+	}
+	if tile != nil && tileExamine(tile) != "" {
+		opts = append(opts, examineOption(tileName(tile), tileExamine(tile)))
+	}
+	return opts
+}
+
+// slotOptions lists the options for the item in a panel slot.
+func slotOptions(ref SlotRef) []MenuOption {
+	switch ref.Panel {
+	case PanelEquipment:
+		return equipmentOptions(equipmentSlotOrder[ref.Index])
+	case PanelBank:
+		return bankOptions(ref.Index)
+	case PanelBankTab:
+		return nil
+	default:
+		return itemOptions(ref.Index)
+	}
+}
+
+// optionsAt lists the options for whatever is under the mouse.
+func optionsAt(mouse rl.Vector2) []MenuOption {
+	if ref, ok := panelSlotAt(mouse); ok {
+		return slotOptions(ref)
+	}
+	if bankOpen && showInventory && rl.CheckCollisionPointRec(mouse, bankPanelRect()) {
+		return nil
+	}
+	return tileOptions(Point{int(mouse.X) / TileSize, int(mouse.Y) / TileSize})
+}
+
+// runOption does o. Doing anything at all puts down an item picked with
+// "Use", unless that's what o does.
+func runOption(o MenuOption) {
+	usingItem = -1
+	o.Do()
+}
+
+// runDefault does the first of opts, which is what a left-click means.
+func runDefault(opts []MenuOption) {
+	if len(opts) > 0 {
+		runOption(opts[0])
+	}
+}
+
+func (o MenuOption) text() string {
+	if o.Target == "" {
+		return o.Verb
+	}
+	return o.Verb + " " + o.Target
+}
+
+// openContextMenu opens a menu of opts at pos, kept on screen.
+func openContextMenu(pos rl.Vector2, opts []MenuOption) {
+	m := &ContextMenu{Options: append(opts, MenuOption{Verb: "Cancel", Do: func() {}})}
+	r := m.rect()
+	m.Pos.X = min(pos.X, ScreenWidth-r.Width)
+	m.Pos.Y = min(pos.Y, ScreenHeight-r.Height)
+	contextMenu = m
+}
+
+func (m *ContextMenu) rect() rl.Rectangle {
+	width := rl.MeasureText(menuTitle, menuFontSize)
+	for _, o := range m.Options {
+		width = max(width, rl.MeasureText(o.text(), menuFontSize))
+	}
+	return rl.NewRectangle(m.Pos.X, m.Pos.Y, float32(width+menuPadding*2), float32((len(m.Options)+1)*menuRowHeight))
+}
+
+// rowRect is the i'th option, below the title row.
+func (m *ContextMenu) rowRect(i int) rl.Rectangle {
+	r := m.rect()
+	return rl.NewRectangle(r.X, r.Y+float32((i+1)*menuRowHeight), r.Width, menuRowHeight)
+}
+
+// updateContextMenu opens a menu on right-click and picks from it on
+// left-click. It reports whether it used up this frame's click.
+func updateContextMenu() bool {
+	mouse := rl.GetMousePosition()
+
+	if rl.IsMouseButtonPressed(rl.MouseRightButton) {
+		contextMenu = nil
+		if opts := optionsAt(mouse); len(opts) > 0 {
+			openContextMenu(mouse, opts)
+		}
+		return true
+	}
+	if contextMenu == nil {
+		return false
+	}
+
+	if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		m := contextMenu
+		contextMenu = nil
+		for i, o := range m.Options {
+			if rl.CheckCollisionPointRec(mouse, m.rowRect(i)) {
+				runOption(o)
+			}
+		}
+		return true
+	}
+
+	// Wandering away from the menu closes it.
+	r := contextMenu.rect()
+	margin := float32(menuRowHeight)
+	if !rl.CheckCollisionPointRec(mouse, rl.NewRectangle(r.X-margin, r.Y-margin, r.Width+margin*2, r.Height+margin*2)) {
+		contextMenu = nil
+	}
+	return false
+}
+
+// drawOptionText draws the verb in white and the target in orange.
+func drawOptionText(o MenuOption, x, y int32) {
+	rl.DrawText(o.Verb, x, y, menuFontSize, rl.White)
+	if o.Target != "" {
+		offset := rl.MeasureText(o.Verb+" ", menuFontSize)
+		rl.DrawText(o.Target, x+offset, y, menuFontSize, rl.Orange)
+	}
+}
+
+func drawContextMenu() {
+	if contextMenu == nil {
+		return
+	}
+	mouse := rl.GetMousePosition()
+	r := contextMenu.rect()
+
+	rl.DrawRectangleRec(r, rl.Fade(rl.Black, 0.85))
+	rl.DrawRectangleLinesEx(r, 1, rl.Gray)
+	rl.DrawText(menuTitle, int32(r.X)+menuPadding, int32(r.Y)+2, menuFontSize, rl.Beige)
+
+	for i, o := range contextMenu.Options {
+		row := contextMenu.rowRect(i)
+		if rl.CheckCollisionPointRec(mouse, row) {
+			rl.DrawRectangleRec(row, rl.Fade(rl.Gray, 0.5))
+		}
+		drawOptionText(o, int32(row.X)+menuPadding, int32(row.Y)+2)
+	}
+}
+
+// drawHoverAction shows what left-clicking would do, top-left.
+func drawHoverAction() {
+	if contextMenu != nil || drag != nil {
+		return
+	}
+	opts := optionsAt(rl.GetMousePosition())
+	if len(opts) == 0 {
+		return
+	}
+
+	more := ""
+	if len(opts) > 1 {
+		more = fmt.Sprintf(" / %d more options", len(opts)-1)
+	}
+	width := rl.MeasureText(opts[0].text()+more, menuFontSize)
+	rl.DrawRectangle(hoverActionX, hoverActionY, width+menuPadding*2, menuRowHeight, rl.Fade(rl.Black, 0.6))
+	drawOptionText(opts[0], hoverActionX+menuPadding, hoverActionY+2)
+	rl.DrawText(more, hoverActionX+menuPadding+rl.MeasureText(opts[0].text(), menuFontSize), hoverActionY+2, menuFontSize, rl.White)
+}
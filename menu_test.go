@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// findOption returns the option with the given verb, or fails t.
+func findOption(t *testing.T, opts []MenuOption, verb string) MenuOption {
+	t.Helper()
+	for _, o := range opts {
+		if o.Verb == verb {
+			return o
+		}
+	}
+	t.Fatalf("no %q in %v", verb, opts)
+	return MenuOption{}
+}
+
+func newUsePlayer(t *testing.T) {
+	t.Helper()
+	loadItems(t)
+	player = NewPlayer(0, 0, NewMap(5, 5), rl.Texture2D{}, rl.Texture2D{})
+	player.Inventory.AddByID("tinderbox", 1)
+	player.Inventory.AddByID("logs", 1)
+	usingItem = -1
+}
+
+func TestUseFollowsTheItem(t *testing.T) {
+	newUsePlayer(t)
+	runOption(findOption(t, itemOptions(0), "Use"))
+	if pickedItem() != 0 {
+		t.Fatalf("picked slot %d, want 0", pickedItem())
+	}
+
+	// Using the tinderbox on the logs lights them.
+	runOption(findOption(t, itemOptions(1), "Use"))
+	if _, ok := player.Actions.Current().(*LightFireAction); !ok {
+		t.Fatalf("using a tinderbox on logs queued %T", player.Actions.Current())
+	}
+}
+
+func TestUseForgetsMovedItem(t *testing.T) {
+	newUsePlayer(t)
+	runOption(findOption(t, itemOptions(0), "Use"))
+
+	// The tinderbox is dragged away and a knife put in its slot.
+	player.Inventory.Set(2, player.Inventory.Get(0))
+	player.Inventory.Set(0, ItemSlot{ID: "knife", Count: 1})
+
+	if pickedItem() != -1 {
+		t.Fatal("still using a slot whose item has gone")
+	}
+	if opts := itemOptions(1); opts[0].Verb == "Use" && len(opts) == 1 {
+		t.Fatal("logs still offer to have the old slot used on them")
+	}
+}
+
+func TestUseOptionRechecksSlot(t *testing.T) {
+	newUsePlayer(t)
+	runOption(findOption(t, itemOptions(0), "Use"))
+	use := itemOptions(1)[0]
+
+	// The menu stays open while the tinderbox is dropped.
+	player.Inventory.Set(0, ItemSlot{ID: "knife", Count: 1})
+	runOption(use)
+	if player.Actions.Current() != nil {
+		t.Fatalf("a stale Use ran and queued %T", player.Actions.Current())
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+// Occupancy tracks which tiles are currently taken by entities. Terrain
+// walkability stays on Tile; this only covers things that come and go, so it
+// is cleared and rebuilt every frame.
+type Occupancy struct {
+	width  int
+	height int
+	cells  []int
+}
+
+func NewOccupancy(width, height int) *Occupancy {
+	return &Occupancy{
+		width:  width,
+		height: height,
+		cells:  make([]int, width*height),
+	}
+}
+
+func (o *Occupancy) index(p Point) int {
+	if p.X < 0 || p.X >= o.width || p.Y < 0 || p.Y >= o.height {
+		return -1
+	}
+	return p.Y*o.width + p.X
+}
+
+func (o *Occupancy) Clear() {
+	for i := range o.cells {
+		o.cells[i] = 0
+	}
+}
+
+func (o *Occupancy) Add(p Point) {
+	if i := o.index(p); i >= 0 {
+		o.cells[i]++
+	}
+}
+
+func (o *Occupancy) Remove(p Point) {
+	if i := o.index(p); i >= 0 && o.cells[i] > 0 {
+		o.cells[i]--
+	}
+}
+
+func (o *Occupancy) IsOccupied(p Point) bool {
+	i := o.index(p)
+	return i >= 0 && o.cells[i] > 0
+}
@@ -11,6 +11,9 @@ type Node struct {
 	F      float64
 	Parent *Node
 	Index  int
+	// Shortcut is set when the node was reached from Parent by taking a
+	// shortcut rather than walking.
+	Shortcut bool
 }
 
 type PriorityQueue []*Node
@@ -46,7 +49,51 @@ func neighbors(p Point) []Point {
 	}
 }
 
-func FindPath(start, goal Point, m *Map) []Point {
+// Pathfinder plans a 4-connected path from start to goal. The returned path
+// includes both ends, or is nil if goal cannot be reached.
+type Pathfinder interface {
+	FindPath(start, goal Point, m *Map) []Point
+}
+
+// AStar handles any tile costs. With Agility set it also takes any shortcut
+// open at that level.
+type AStar struct {
+	Agility int
+}
+
+// FindPath picks the cheapest planner for m: Jump Point Search when every
+// walkable tile costs the same, A* otherwise. Both take any shortcut open at
+// the given Agility level.
+func FindPath(start, goal Point, m *Map, agility int) []Point {
+	return PathfinderFor(m, agility).FindPath(start, goal, m)
+}
+
+func PathfinderFor(m *Map, agility int) Pathfinder {
+	if m.UniformCost() {
+		return JumpPointSearch{Agility: agility}
+	}
+	return AStar{Agility: agility}
+}
+
+// pathPassable reports whether a planner may put next on a path to goal. The
+// goal may be occupied (walking up to an enemy), but nothing else on the way
+// can be.
+func pathPassable(next, goal Point, m *Map) bool {
+	if next == goal {
+		tile := m.GetTile(next.X, next.Y)
+		return tile != nil && tile.IsWalkable()
+	}
+	return m.CanEnter(next)
+}
+
+// pathEdge is a move A* can make: one step to a neighbour, or a whole
+// shortcut.
+type pathEdge struct {
+	to   Point
+	cost float64
+}
+
+func (a AStar) FindPath(start, goal Point, m *Map) []Point {
 	open := make(PriorityQueue, 0)
 	heap.Init(&open)
 
@@ -66,15 +113,29 @@ func FindPath(start, goal Point, m *Map) []Point {
 
 		visited[current.Point] = true
 
+		var edges []pathEdge
 		for _, next := range neighbors(current.Point) {
-			if m.GetTile(next.X, next.Y) == nil || !m.GetTile(next.X, next.Y).IsWalkable() {
-				continue
+			if pathPassable(next, goal, m) {
+				edges = append(edges, pathEdge{next, m.GetTile(next.X, next.Y).MoveCost()})
+			}
+		}
+		for _, s := range m.shortcutEdges(current.Point, a.Agility) {
+			far := s.End()
+			if far == current.Point {
+				far = s.Start()
 			}
+			if pathPassable(far, goal, m) {
+				edges = append(edges, pathEdge{far, s.Cost()})
+			}
+		}
+
+		for _, e := range edges {
+			next := e.to
 			if visited[next] {
 				continue
 			}
 
-			newCost := costSoFar[current.Point] + 1
+			newCost := costSoFar[current.Point] + e.cost
 			if oldCost, ok := costSoFar[next]; !ok || newCost < oldCost {
 				costSoFar[next] = newCost
 				h := heuristic(next, goal)
@@ -99,3 +160,83 @@ func reconstructPath(end *Node) []Point {
 	}
 	return path
 }
+
+// bodyHalfExtent is half the width, in tiles, of the box an agent sweeps
+// along a straight segment. Just under half a tile lets it hug walls but never
+// squeeze diagonally between two blocked tiles.
+const bodyHalfExtent = 0.45
+
+// segmentClear reports whether an agent can move in a straight line from the
+// centre of a to the centre of b, touching only tiles accepted by passable.
+// It works a row at a time: while the box overlaps a row, its centre covers a
+// span of the line, and every tile that span's box reaches is checked.
+func segmentClear(a, b Point, passable func(Point) bool) bool {
+	ax, ay := float64(a.X)+0.5, float64(a.Y)+0.5
+	dx, dy := float64(b.X-a.X), float64(b.Y-a.Y)
+
+	minY := int(math.Floor(min(ay, ay+dy) - bodyHalfExtent))
+	maxY := int(math.Floor(max(ay, ay+dy) + bodyHalfExtent))
+	for y := minY; y <= maxY; y++ {
+		// The part of the segment where the box overlaps row y.
+		t0, t1 := 0.0, 1.0
+		if dy != 0 {
+			t0 = (float64(y) - bodyHalfExtent - ay) / dy
+			t1 = (float64(y+1) + bodyHalfExtent - ay) / dy
+			if t0 > t1 {
+				t0, t1 = t1, t0
+			}
+			t0, t1 = max(t0, 0), min(t1, 1)
+			if t0 > t1 {
+				continue
+			}
+		}
+
+		x0, x1 := ax+dx*t0, ax+dx*t1
+		minX := int(math.Floor(min(x0, x1) - bodyHalfExtent))
+		maxX := int(math.Floor(max(x0, x1) + bodyHalfExtent))
+		for x := minX; x <= maxX; x++ {
+			if !passable(Point{x, y}) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// LineOfSight reports whether a straight walk from a to b crosses only
+// walkable terrain.
+func (m *Map) LineOfSight(a, b Point) bool {
+	return segmentClear(a, b, func(p Point) bool {
+		tile := m.GetTile(p.X, p.Y)
+		return tile != nil && tile.IsWalkable()
+	})
+}
+
+// SmoothPath drops waypoints from a tile path wherever the agent could walk
+// straight past them, so movement across open ground is no longer a zigzag.
+// The first and last points are always kept. Tiles along each remaining
+// segment are walkable and, apart from the goal, unoccupied.
+func SmoothPath(path []Point, m *Map) []Point {
+	if len(path) < 3 {
+		return path
+	}
+
+	goal := path[len(path)-1]
+	passable := func(p Point) bool {
+		if p == goal {
+			tile := m.GetTile(p.X, p.Y)
+			return tile != nil && tile.IsWalkable()
+		}
+		return m.CanEnter(p) || p == path[0]
+	}
+
+	smoothed := []Point{path[0]}
+	anchor := path[0]
+	for i := 2; i < len(path); i++ {
+		if !segmentClear(anchor, path[i], passable) {
+			anchor = path[i-1]
+			smoothed = append(smoothed, anchor)
+		}
+	}
+	return append(smoothed, goal)
+}
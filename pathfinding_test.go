@@ -0,0 +1,104 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// sweptTiles lists every tile the agent's box touches on the way from the
+// centre of a to the centre of b, by sampling the way finely.
+func sweptTiles(a, b Point) []Point {
+	var tiles []Point
+	const n = 200
+	for s := range n + 1 {
+		t := float64(s) / n
+		cx := float64(a.X) + 0.5 + float64(b.X-a.X)*t
+		cy := float64(a.Y) + 0.5 + float64(b.Y-a.Y)*t
+		for _, dx := range []float64{-bodyHalfExtent, bodyHalfExtent} {
+			for _, dy := range []float64{-bodyHalfExtent, bodyHalfExtent} {
+				p := Point{int(math.Floor(cx + dx)), int(math.Floor(cy + dy))}
+				if !slices.Contains(tiles, p) {
+					tiles = append(tiles, p)
+				}
+			}
+		}
+	}
+	return tiles
+}
+
+func TestSmoothPathSegmentsClear(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for i := range 1000 {
+		m := randomMap(rng, 5+rng.Intn(20), 5+rng.Intn(20), 0.3*rng.Float64(), 0.1*rng.Float64())
+		start, goal := randomPoint(rng, m), randomPoint(rng, m)
+		if !m.CanEnter(start) {
+			continue
+		}
+		path := AStar{}.FindPath(start, goal, m)
+		if path == nil {
+			continue
+		}
+
+		smoothed := SmoothPath(path, m)
+		if smoothed[0] != start || smoothed[len(smoothed)-1] != goal {
+			t.Fatalf("map %d: smoothed %v doesn't run from %v to %v", i, smoothed, start, goal)
+		}
+		if len(smoothed) > len(path) {
+			t.Fatalf("map %d: smoothing grew the path from %d to %d points", i, len(path), len(smoothed))
+		}
+
+		// Every waypoint kept is one of the originals, in order.
+		j := 0
+		for _, p := range smoothed {
+			for j < len(path) && path[j] != p {
+				j++
+			}
+			if j == len(path) {
+				t.Fatalf("map %d: smoothed %v isn't a subsequence of %v", i, smoothed, path)
+			}
+		}
+
+		for k := 1; k < len(smoothed); k++ {
+			a, b := smoothed[k-1], smoothed[k]
+			for _, p := range sweptTiles(a, b) {
+				if p == start {
+					continue
+				}
+				if tile := m.GetTile(p.X, p.Y); tile == nil || !tile.IsWalkable() {
+					t.Fatalf("map %d: segment %v -> %v clips the wall at %v", i, a, b, p)
+				}
+				if p != goal && m.Occupancy.IsOccupied(p) {
+					t.Fatalf("map %d: segment %v -> %v runs through an entity at %v", i, a, b, p)
+				}
+			}
+		}
+	}
+}
+
+func TestSegmentClear(t *testing.T) {
+	m := NewMap(6, 6)
+	m.SetTile(2, 1, TileWall)
+	m.SetTile(1, 2, TileWall)
+	m.SetTile(4, 4, TileWall)
+
+	tests := []struct {
+		a, b Point
+		want bool
+	}{
+		{Point{0, 0}, Point{0, 5}, true},
+		{Point{0, 0}, Point{5, 0}, true},
+		// Hugging a wall is fine, squeezing between two diagonal ones isn't.
+		{Point{3, 0}, Point{3, 5}, true},
+		{Point{1, 1}, Point{2, 2}, false},
+		{Point{0, 0}, Point{5, 5}, false},
+		{Point{5, 3}, Point{3, 5}, false},
+		{Point{3, 3}, Point{3, 3}, true},
+	}
+	for _, tt := range tests {
+		if got := m.LineOfSight(tt.a, tt.b); got != tt.want {
+			t.Errorf("LineOfSight(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
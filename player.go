@@ -6,67 +6,115 @@ import (
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
-var gatherSettings = map[int]struct {
-	Label string
-	Item  string
-	Time  float32
-}{
-	TileTree:  {"Chopping...", "Logs", 2.0},
-	TileRock:  {"Mining...", "Ore", 2.5},
-	TileWater: {"Fishing...", "Fish", 3.0},
-}
+const (
+	// pathWaitTime is how long the player waits for an entity to clear the
+	// way before planning around it.
+	pathWaitTime = float32(0.5)
+	// repairLookahead is how far along the old path a detour tries to rejoin
+	// before falling back to replanning the whole route.
+	repairLookahead = 4
+)
 
 type Player struct {
-	Pos           rl.Vector2
-	Size          rl.Vector2
-	Speed         float32
-	Color         rl.Color
-	Target        rl.Vector2
-	Map           *Map
-	Path          []Point
-	Inventory     *Inventory
-	Gathering     bool
-	GatherTarget  Point
-	GatherLabel   string
-	GatherTimer   float32
-	GatherItem    string
-	PendingGather *Point
-	Equipment     *Equipment
-	Texture       rl.Texture2D
-	Health        int
-	MaxHealth     int
+	Pos         rl.Vector2
+	PrevPos     rl.Vector2 // Pos before the last step, for interpolation
+	Size        rl.Vector2
+	Speed       float32 // walking speed; see MoveSpeed
+	Running     bool
+	RunEnergy   float32
+	Color       rl.Color
+	Target      rl.Vector2
+	Map         *Map
+	Path        []Point
+	Inventory   *Inventory
+	Actions     *ActionQueue
+	WaitTimer   float32 // how long the next step has been blocked
+	SmoothPaths bool    // walk straight across open ground instead of tile by tile
+	Equipment   *Equipment
+	Skills      *Skills
+	Texture     rl.Texture2D
+	Health      int
+	MaxHealth   int
+	Traversal   *Traversal // crossing an agility shortcut, if not nil
+	Bank        *Bank
 }
 
 func NewPlayer(x, y float32, m *Map, texture rl.Texture2D, itemTexture rl.Texture2D) Player {
 	return Player{
-		Pos:       rl.NewVector2(x, y),
-		Size:      rl.NewVector2(32, 32),
-		Speed:     180,
-		Color:     rl.Brown,
-		Target:    rl.NewVector2(x, y),
-		Map:       m,
-		Inventory: NewInventory(itemTexture),
-		Equipment: NewEquipment(),
-		Texture:   texture,
-		Health:    100,
-		MaxHealth: 100,
+		Pos:         rl.NewVector2(x, y),
+		PrevPos:     rl.NewVector2(x, y),
+		Size:        rl.NewVector2(32, 32),
+		Speed:       180,
+		RunEnergy:   MaxRunEnergy,
+		Color:       rl.Brown,
+		Target:      rl.NewVector2(x, y),
+		Map:         m,
+		Inventory:   NewInventory(itemTexture),
+		Actions:     &ActionQueue{},
+		Equipment:   NewEquipment(),
+		Skills:      NewSkills(),
+		Bank:        NewBank(),
+		Texture:     texture,
+		Health:      100,
+		MaxHealth:   startingHitpoints * 10,
+		SmoothPaths: true,
 	}
 }
 
-func (p *Player) MoveToTile(tileX, tileY int) {
-	start := Point{
+// CurrentTile returns the tile under the centre of the player's sprite.
+func (p *Player) CurrentTile() Point {
+	return Point{
 		X: int(p.Pos.X+p.Size.X/2) / TileSize,
 		Y: int(p.Pos.Y+p.Size.Y/2) / TileSize,
 	}
+}
+
+// findPath plans a route for the player, taking any shortcut their Agility
+// level allows.
+func (p *Player) findPath(start, goal Point) []Point {
+	return FindPath(start, goal, p.Map, p.Skills.Level(SkillAgility))
+}
+
+// MoveToTile plans a path to the given tile and starts walking it. It reports
+// whether a path was found.
+func (p *Player) MoveToTile(tileX, tileY int) bool {
+	start := p.CurrentTile()
 	goal := Point{tileX, tileY}
-	path := FindPath(start, goal, p.Map)
+	path := p.findPath(start, goal)
 
 	if len(path) == 0 {
 		fmt.Println("No valid path to target:", goal)
-		p.PendingGather = nil
+		return false
+	}
+
+	p.setPath(path)
+	return true
+}
+
+// Step walks one tile in dir, replacing whatever the player was doing. Calls
+// made while a step is still under way are ignored, so holding a direction
+// walks tile by tile.
+func (p *Player) Step(dir Point) {
+	if w, ok := p.Actions.Current().(*WalkAction); ok && w.Step {
+		return
+	}
+
+	next := p.CurrentTile()
+	next.X += dir.X
+	next.Y += dir.Y
+	if !p.Map.CanEnter(next) {
+		p.Actions.Clear()
 		return
 	}
+	p.Actions.Replace(NewStepAction(p, next))
+}
 
+// setPath installs a freshly planned tile path, smoothing it first if the
+// player has any-angle movement turned on.
+func (p *Player) setPath(path []Point) {
+	if p.SmoothPaths {
+		path = SmoothPath(path, p.Map)
+	}
 	p.Path = path
 }
 
@@ -75,10 +123,7 @@ func (p *Player) FindAdjacentWalkable(target Point) *Point {
 		{X: 0, Y: -1}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: -1, Y: 0},
 	}
 
-	start := Point{
-		X: int(p.Pos.X+p.Size.X/2) / TileSize,
-		Y: int(p.Pos.Y+p.Size.Y/2) / TileSize,
-	}
+	start := p.CurrentTile()
 
 	var bestPath []Point
 	var bestAdj *Point
@@ -88,7 +133,7 @@ func (p *Player) FindAdjacentWalkable(target Point) *Point {
 		tile := p.Map.GetTile(adj.X, adj.Y)
 
 		if tile != nil && tile.IsWalkable() {
-			path := FindPath(start, adj, p.Map)
+			path := p.findPath(start, adj)
 			if len(path) > 0 && (bestPath == nil || len(path) < len(bestPath)) {
 				bestPath = path
 				bestAdj = &adj
@@ -99,50 +144,119 @@ func (p *Player) FindAdjacentWalkable(target Point) *Point {
 	return bestAdj
 }
 
-func (p *Player) Update(dt float32) {
+func (p *Player) Update(t Tick) {
+	p.PrevPos = p.Pos
+
+	// Obstacles play out on their own; anything queued meanwhile waits.
+	if p.Traversal != nil {
+		p.updateTraversal(t)
+		return
+	}
 	if len(p.Path) > 0 {
+		if s, route := p.Map.ShortcutFrom(p.CurrentTile(), p.Path[0], p.Skills.Level(SkillAgility)); s != nil {
+			p.Path = p.Path[1:]
+			p.beginTraversal(s, route)
+			return
+		}
+	}
+
+	if len(p.Path) > 0 && !p.stepClear(0) {
+		p.handleBlockedStep(t.Dt)
+	} else if len(p.Path) > 0 {
+		p.WaitTimer = 0
 		next := p.Path[0]
 		centerX := float32(next.X*TileSize + TileSize/2)
 		centerY := float32(next.Y*TileSize + TileSize/2)
 		target := rl.NewVector2(centerX-p.Size.X/2, centerY-p.Size.Y/2)
 
+		// Snap once the waypoint is within one step's reach; at run speed
+		// a fixed step is wide enough to overshoot and oscillate otherwise.
+		step := p.MoveSpeed() * t.Dt
 		dir := rl.Vector2Subtract(target, p.Pos)
-		if rl.Vector2Length(dir) < 2 {
+		if rl.Vector2Length(dir) <= max(step, 2) {
 			p.Pos = target
 			p.Path = p.Path[1:]
 		} else {
 			dir = rl.Vector2Normalize(dir)
-			dir = rl.Vector2Scale(dir, p.Speed*dt)
+			dir = rl.Vector2Scale(dir, step)
 			p.Pos = rl.Vector2Add(p.Pos, dir)
 		}
 	}
 
-	if p.Gathering {
-		p.GatherTimer -= dt
-		if p.GatherTimer <= 0 {
-			p.FinishGather()
+	p.updateRunEnergy(t, p.Pos != p.PrevPos)
+	p.Actions.Update(t)
+}
+
+// stepClear reports whether the player can still walk to waypoint i of its
+// path. The player's own tile is never in the way. Terrain is checked
+// everywhere else, and occupancy everywhere but the final tile, so walking
+// up to an enemy still works. With smoothing on, every tile along the
+// straight segment counts, not just the waypoint.
+func (p *Player) stepClear(i int) bool {
+	here := p.CurrentTile()
+	goal := p.Path[len(p.Path)-1]
+
+	return segmentClear(here, p.Path[i], func(t Point) bool {
+		// The player can always step off its own tile, even if something
+		// (like a fire it just lit) appeared underneath it.
+		if t == here {
+			return true
 		}
-	}
+		tile := p.Map.GetTile(t.X, t.Y)
+		if tile == nil || !tile.IsWalkable() {
+			return false
+		}
+		if t == goal {
+			return true
+		}
+		return !p.Map.Occupancy.IsOccupied(t)
+	})
+}
 
-	if p.PendingGather != nil && len(p.Path) == 0 {
-		playerTileX := int(p.Pos.X+p.Size.X/2) / TileSize
-		playerTileY := int(p.Pos.Y+p.Size.Y/2) / TileSize
+func (p *Player) handleBlockedStep(dt float32) {
+	next := p.Path[0]
 
-		dx := p.PendingGather.X - playerTileX
-		dy := p.PendingGather.Y - playerTileY
+	// A wall or closed door will not move out of the way, so replan at once.
+	// An entity probably will, so give it a moment first.
+	if tile := p.Map.GetTile(next.X, next.Y); tile != nil && tile.IsWalkable() {
+		p.WaitTimer += dt
+		if p.WaitTimer < pathWaitTime {
+			return
+		}
+	}
+	p.WaitTimer = 0
 
-		fmt.Printf("Arrived near gather target? Player at (%d,%d), target (%d,%d)\n", playerTileX, playerTileY, p.PendingGather.X, p.PendingGather.Y)
+	if !p.repairPath() {
+		fmt.Println("Path blocked at", next)
+		p.Path = nil
+	}
+}
 
-		if abs(dx)+abs(dy) <= 1 {
-			p.startGather(p.PendingGather.X, p.PendingGather.Y)
-		} else {
-			fmt.Println("Target not adjacent after walking — skipping gather")
+// repairPath plans a detour from the player's tile. It first tries to rejoin
+// the existing path a few steps ahead and only replans to the goal if that
+// fails.
+func (p *Player) repairPath() bool {
+	here := p.CurrentTile()
+	last := len(p.Path) - 1
+
+	if rejoin := min(repairLookahead, last); rejoin > 0 && rejoin < last && p.Map.CanEnter(p.Path[rejoin]) {
+		if detour := p.findPath(here, p.Path[rejoin]); len(detour) > 0 {
+			p.setPath(append(detour, p.Path[rejoin+1:]...))
+			return true
 		}
-		p.PendingGather = nil
 	}
+
+	path := p.findPath(here, p.Path[last])
+	if len(path) == 0 {
+		return false
+	}
+	p.setPath(path)
+	return true
 }
 
-func (p *Player) Draw() {
+// Draw renders the player alpha of the way between its last two simulated
+// positions.
+func (p *Player) Draw(alpha float32) {
 	for _, step := range p.Path {
 		center := rl.NewVector2(float32(step.X*TileSize+TileSize/2), float32(step.Y*TileSize+TileSize/2))
 		rl.DrawCircleV(center, 2, rl.Red)
@@ -155,167 +269,106 @@ func (p *Player) Draw() {
 		Height: TileSize,
 	}
 
-	rl.DrawTextureRec(p.Texture, source, p.Pos, rl.White)
+	rl.DrawTextureRec(p.Texture, source, rl.Vector2Lerp(p.PrevPos, p.Pos, alpha), rl.White)
 }
 
-func (p *Player) DrawInventory(x, y int) {
-	slots := p.Inventory.Slots()
-	boxSize := 40
-	columns := 7
-
-	for i, slot := range slots {
-		cx := x + (i%columns)*(boxSize+4)
-		cy := y + (i/columns)*(boxSize+4)
-		rect := rl.NewRectangle(float32(cx), float32(cy), float32(boxSize), float32(boxSize))
-
-		rl.DrawRectangleRec(rect, rl.LightGray)
-		rl.DrawRectangleLinesEx(rect, 1, rl.DarkGray)
-
-		if slot.Name != "" {
-			rl.DrawText(slot.Name[:1], int32(cx+4), int32(cy+2), 20, rl.Black)
+const (
+	slotSize         = 40
+	slotPadding      = 4
+	inventoryColumns = 7
+)
 
-			rl.DrawTextureRec(player.Inventory.ItemsTexture, slot.FrameRect, rl.NewVector2(float32(cx), float32(cy)), rl.White)
-			rl.DrawText(fmt.Sprintf("%d", slot.Count), int32(cx+4), int32(cy+20), 16, rl.DarkBlue)
-		}
-	}
+// equipmentSlotOrder is the top-to-bottom order of the equipment panel.
+var equipmentSlotOrder = []EquipmentSlot{
+	SlotHead,
+	SlotBody,
+	SlotLegs,
+	SlotWeapon,
+	SlotShield,
+	SlotAmmo,
 }
 
-func (p *Player) TryGatherAt(tileX, tileY int) {
-	tile := p.Map.GetTile(tileX, tileY)
-	if tile == nil || !tile.IsGatherable() {
-		fmt.Println("Tile not gatherable")
-		return
-	}
-
-	playerTileX := int(p.Pos.X+p.Size.X/2) / TileSize
-	playerTileY := int(p.Pos.Y+p.Size.Y/2) / TileSize
-
-	dx := tileX - playerTileX
-	dy := tileY - playerTileY
-
-	if abs(dx)+abs(dy) <= 1 {
-		fmt.Println("Gathering immediately at", tileX, tileY)
-		p.startGather(tileX, tileY)
-	} else {
-		adj := p.FindAdjacentWalkable(Point{tileX, tileY})
-		if adj == nil {
-			fmt.Println("No adjacent walkable tile to gather target")
-			return
-		}
-		p.MoveToTile(adj.X, adj.Y)
-		p.PendingGather = &Point{tileX, tileY}
-	}
+// gridSlotRect is where slot i goes in a grid of slots at x, y.
+func gridSlotRect(x, y, i, columns int) rl.Rectangle {
+	cx := x + (i%columns)*(slotSize+slotPadding)
+	cy := y + (i/columns)*(slotSize+slotPadding)
+	return rl.NewRectangle(float32(cx), float32(cy), slotSize, slotSize)
 }
 
-func (p *Player) startGather(tileX, tileY int) {
-	p.Gathering = true
-	p.GatherTarget = Point{tileX, tileY}
-
-	tile := p.Map.GetTile(tileX, tileY)
+// inventorySlotRect is where inventory slot i is drawn for a panel at x, y.
+func inventorySlotRect(x, y, i int) rl.Rectangle {
+	return gridSlotRect(x, y, i, inventoryColumns)
+}
 
-	if settings, ok := gatherSettings[tile.Type]; ok {
-		p.Gathering = true
-		p.GatherTarget = Point{tileX, tileY}
-		p.GatherLabel = settings.Label
-		p.GatherItem = settings.Item
-		p.GatherTimer = settings.Time
-	} else {
-		fmt.Println("Invalid gather target")
-	}
+// equipmentSlotRect is where the i'th slot of equipmentSlotOrder is drawn
+// for a panel at x, y.
+func equipmentSlotRect(x, y, i int) rl.Rectangle {
+	return rl.NewRectangle(float32(x), float32(y+i*(slotSize+slotPadding)), slotSize, slotSize)
 }
 
-func (p *Player) FinishGather() {
-	tile := p.Map.GetTile(p.GatherTarget.X, p.GatherTarget.Y)
-	if tile == nil {
-		p.Gathering = false
-		return
+// drawItem draws an item's sprite and initial in rect, faded by alpha.
+func drawItem(item ItemSlot, rect rl.Rectangle, alpha float32, showCount bool) {
+	def := item.Def()
+	rl.DrawText(def.Name[:1], int32(rect.X+4), int32(rect.Y+2), 20, rl.Fade(rl.Black, alpha))
+	rl.DrawTextureRec(player.Inventory.ItemsTexture, def.FrameRect(), rl.NewVector2(rect.X, rect.Y), rl.Fade(rl.White, alpha))
+	if showCount {
+		rl.DrawText(fmt.Sprintf("%d", item.Count), int32(rect.X+4), int32(rect.Y+20), 16, rl.Fade(rl.DarkBlue, alpha))
 	}
+}
 
-	switch tile.Type {
-	case TileTree, TileRock:
-		tile.Type = TileGrass
-	}
+func (p *Player) DrawInventory(x, y int) {
+	for i, slot := range p.Inventory.Slots() {
+		rect := inventorySlotRect(x, y, i)
 
-	// Add item to inventory
-	p.Inventory.Add(ItemSlot{
-		Name:  p.GatherItem,
-		Count: 1,
-		Type:  inferItemType(p.GatherItem),
-	})
+		rl.DrawRectangleRec(rect, rl.LightGray)
+		rl.DrawRectangleLinesEx(rect, 1, rl.DarkGray)
 
-	p.Gathering = false
-	p.GatherLabel = ""
+		if !slot.Empty() {
+			alpha := float32(1)
+			if isDragged(SlotRef{PanelInventory, i}) {
+				alpha = 0.3
+			}
+			drawItem(slot, rect, alpha, true)
+		}
+		if i == pickedItem() {
+			rl.DrawRectangleLinesEx(rect, 2, rl.White)
+		}
+	}
 }
 
 func (p *Player) DrawEquipment(x, y int) {
-	boxSize := 40
-	padding := 4
-
-	slotOrder := []EquipmentSlot{
-		SlotHead,
-		SlotBody,
-		SlotLegs,
-		SlotWeapon,
-		SlotShield,
-	}
-
-	for i, slot := range slotOrder {
-		cx := x
-		cy := y + i*(boxSize+padding)
-		rect := rl.NewRectangle(float32(cx), float32(cy), float32(boxSize), float32(boxSize))
+	for i, slot := range equipmentSlotOrder {
+		rect := equipmentSlotRect(x, y, i)
 
 		// Draw slot box
 		rl.DrawRectangleRec(rect, rl.LightGray)
 		rl.DrawRectangleLinesEx(rect, 1, rl.DarkGray)
 
 		// Draw slot label
-		rl.DrawText(string(slot), int32(cx+boxSize+6), int32(cy+12), 16, rl.Black)
+		rl.DrawText(string(slot), int32(rect.X)+slotSize+6, int32(rect.Y)+12, 16, rl.Black)
 
 		item := p.Equipment.Slots[slot]
-		if item.Name != "" {
-
-			rl.DrawTextureRec(player.Inventory.ItemsTexture, item.FrameRect, rl.NewVector2(float32(cx), float32(cy)), rl.White)
-			rl.DrawText(item.Name[:1], int32(cx+4), int32(cy+2), 20, rl.Black)
-		}
-	}
-}
-
-func (p *Player) CheckInventoryClick(x, y int) (clickedIndex int, clickedUI bool) {
-	slots := p.Inventory.Slots()
-	boxSize := 40
-	columns := 7
-	mouse := rl.GetMousePosition()
-
-	clickedIndex = -1
-
-	for i := range slots {
-		cx := x + (i%columns)*(boxSize+4)
-		cy := y + (i/columns)*(boxSize+4)
-		rect := rl.NewRectangle(float32(cx), float32(cy), float32(boxSize), float32(boxSize))
-
-		if rl.CheckCollisionPointRec(mouse, rect) {
-			clickedUI = true
-			if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
-				clickedIndex = i
+		if !item.Empty() {
+			alpha := float32(1)
+			if isDragged(SlotRef{PanelEquipment, i}) {
+				alpha = 0.3
 			}
+			drawItem(item, rect, alpha, slot == SlotAmmo)
 		}
 	}
+}
 
-	return clickedIndex, clickedUI
+// CheckInventoryClick reports whether the mouse is over the inventory, so
+// the click shouldn't reach the map.
+func (p *Player) CheckInventoryClick(x, y int) (clickedUI bool) {
+	return p.GetHoveredInventoryIndex(x, y) >= 0
 }
 
 func (p *Player) GetHoveredInventoryIndex(x, y int) int {
-	slots := p.Inventory.Slots()
-	boxSize := 40
-	columns := 7
 	mouse := rl.GetMousePosition()
 
-	for i := range slots {
-		cx := x + (i%columns)*(boxSize+4)
-		cy := y + (i/columns)*(boxSize+4)
-		rect := rl.NewRectangle(float32(cx), float32(cy), float32(boxSize), float32(boxSize))
-
-		if rl.CheckCollisionPointRec(mouse, rect) {
+	for i := range p.Inventory.Slots() {
+		if rl.CheckCollisionPointRec(mouse, inventorySlotRect(x, y, i)) {
 			return i
 		}
 	}
@@ -323,52 +376,11 @@ func (p *Player) GetHoveredInventoryIndex(x, y int) int {
 	return -1
 }
 
-func (p *Player) CheckEquipmentClick(x, y int) (EquipmentSlot, bool) {
-	boxSize := 40
-	padding := 4
-
-	slotOrder := []EquipmentSlot{
-		SlotHead,
-		SlotBody,
-		SlotLegs,
-		SlotWeapon,
-		SlotShield,
-	}
-
-	mouse := rl.GetMousePosition()
-
-	for i, slot := range slotOrder {
-		cx := x
-		cy := y + i*(boxSize+padding)
-		rect := rl.NewRectangle(float32(cx), float32(cy), float32(boxSize), float32(boxSize))
-
-		if rl.CheckCollisionPointRec(mouse, rect) && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
-			return slot, true
-		}
-	}
-
-	return "", false
-}
-
 func (p *Player) GetHoveredEquipmentSlot(x, y int) EquipmentSlot {
-	boxSize := 40
-	padding := 4
-
-	slotOrder := []EquipmentSlot{
-		SlotHead,
-		SlotBody,
-		SlotLegs,
-		SlotWeapon,
-		SlotShield,
-	}
-
 	mouse := rl.GetMousePosition()
 
-	for i, slot := range slotOrder {
-		cx := x
-		cy := y + i*(boxSize+padding)
-		rect := rl.NewRectangle(float32(cx), float32(cy), float32(boxSize), float32(boxSize))
-		if rl.CheckCollisionPointRec(mouse, rect) {
+	for i, slot := range equipmentSlotOrder {
+		if rl.CheckCollisionPointRec(mouse, equipmentSlotRect(x, y, i)) {
 			return slot
 		}
 	}
@@ -376,11 +388,23 @@ func (p *Player) GetHoveredEquipmentSlot(x, y int) EquipmentSlot {
 	return ""
 }
 
+// TryCraft makes one of recipe if the player has what it takes. A recipe
+// with a FailChance can still use up its inputs for nothing; the result
+// reports whether anything was made.
 func (p *Player) TryCraft(recipe Recipe) bool {
-	if p.Inventory.HasItems(recipe.Inputs) {
-		p.Inventory.ConsumeItems(recipe.Inputs)
-		p.Inventory.Add(recipe.Output)
-		return true
+	if ok, _ := p.CanMake(recipe); !ok {
+		return false
+	}
+
+	p.Inventory.ConsumeItems(recipe.Inputs)
+	if recipe.FailChance > 0 && sim.Rand.Float32() < recipe.FailChance {
+		showMessage(recipe.FailMessage)
+		return false
+	}
+
+	p.addOrDrop(recipe.Output, p.CurrentTile())
+	if recipe.XP > 0 {
+		p.GainXP(recipe.Skill, recipe.XP)
 	}
-	return false
+	return true
 }
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// walkingPlayer stands at 1,2 on an open 10x5 map, part way along a straight
+// path to 8,2.
+func walkingPlayer() *Player {
+	m := NewMap(10, 5)
+	p := NewPlayer(1*TileSize, 2*TileSize, m, rl.Texture2D{}, rl.Texture2D{})
+	p.SmoothPaths = false
+	for x := 2; x <= 8; x++ {
+		p.Path = append(p.Path, Point{x, 2})
+	}
+	return &p
+}
+
+// checkWalkable fails unless path is a chain of single steps from start to
+// goal over tiles nothing stands on.
+func checkWalkable(t *testing.T, m *Map, path []Point, start, goal Point) {
+	t.Helper()
+	prev := start
+	for _, step := range path {
+		if abs(step.X-prev.X)+abs(step.Y-prev.Y) > 1 {
+			t.Fatalf("path %v jumps from %v to %v", path, prev, step)
+		}
+		if !m.CanEnter(step) {
+			t.Fatalf("path %v goes through %v", path, step)
+		}
+		prev = step
+	}
+	if prev != goal {
+		t.Fatalf("path %v ends at %v, want %v", path, prev, goal)
+	}
+}
+
+func TestRepairPathRejoins(t *testing.T) {
+	p := walkingPlayer()
+	old := slices.Clone(p.Path)
+	p.Map.Occupancy.Add(Point{3, 2})
+
+	if p.stepClear(1) {
+		t.Fatal("stepClear doesn't see the occupied tile")
+	}
+	if !p.repairPath() {
+		t.Fatal("no detour around a single occupied tile")
+	}
+	checkWalkable(t, p.Map, p.Path, p.CurrentTile(), Point{8, 2})
+
+	// The detour rejoins repairLookahead steps along and keeps the rest.
+	rest := old[repairLookahead:]
+	if !slices.Equal(p.Path[len(p.Path)-len(rest):], rest) {
+		t.Fatalf("repaired path %v doesn't end with the old %v", p.Path, rest)
+	}
+}
+
+func TestRepairPathReplans(t *testing.T) {
+	p := walkingPlayer()
+	// Blocking the rejoin point itself forces a route to the goal.
+	p.Map.Occupancy.Add(p.Path[repairLookahead])
+	p.Map.Occupancy.Add(Point{3, 2})
+
+	if !p.repairPath() {
+		t.Fatal("no route around two occupied tiles")
+	}
+	checkWalkable(t, p.Map, p.Path, p.CurrentTile(), Point{8, 2})
+}
+
+func TestRepairPathNoRoute(t *testing.T) {
+	p := walkingPlayer()
+	for y := range p.Map.Height {
+		p.Map.Occupancy.Add(Point{4, y})
+	}
+	if p.repairPath() {
+		t.Fatalf("repaired through a wall of entities: %v", p.Path)
+	}
+}
+
+func TestBlockedStepWaits(t *testing.T) {
+	p := walkingPlayer()
+	old := slices.Clone(p.Path)
+	p.Map.Occupancy.Add(Point{2, 2})
+
+	// An entity gets pathWaitTime to move before the player goes round.
+	p.handleBlockedStep(pathWaitTime / 2)
+	if !slices.Equal(p.Path, old) {
+		t.Fatalf("replanned before waiting: %v", p.Path)
+	}
+	p.handleBlockedStep(pathWaitTime / 2)
+	if slices.Equal(p.Path, old) {
+		t.Fatal("still waiting after pathWaitTime")
+	}
+	checkWalkable(t, p.Map, p.Path, p.CurrentTile(), Point{8, 2})
+	if p.WaitTimer != 0 {
+		t.Fatalf("WaitTimer left at %v", p.WaitTimer)
+	}
+}
+
+func TestStep(t *testing.T) {
+	m := NewMap(3, 3)
+	m.SetTile(0, 1, TileWall)
+	p := NewPlayer(tilePos(1, 1).X, tilePos(1, 1).Y, m, rl.Texture2D{}, rl.Texture2D{})
+
+	p.Step(Point{1, 0})
+	w, ok := p.Actions.Current().(*WalkAction)
+	if !ok || !w.Step || w.Target != (Point{2, 1}) {
+		t.Fatalf("after stepping right the queue holds %+v", p.Actions.Current())
+	}
+
+	// Holding a direction doesn't restart the step under way.
+	p.Step(Point{0, 1})
+	if p.Actions.Current() != w {
+		t.Fatalf("a step under way was replaced by %+v", p.Actions.Current())
+	}
+
+	// Stepping into a wall stops the player rather than queueing anything.
+	p.Actions.Replace(NewWalkAction(&p, Point{1, 2}))
+	p.Step(Point{-1, 0})
+	if !p.Actions.Idle() {
+		t.Fatalf("stepping into a wall left %+v queued", p.Actions.Current())
+	}
+}
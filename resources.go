@@ -0,0 +1,92 @@
+package main
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+const (
+	ResourceNone = iota
+	ResourceTree
+	ResourceOak
+	ResourceWillow
+	ResourceCopper
+	ResourceTin
+	ResourceIron
+	ResourceCoal
+	ResourceFishingSpot
+)
+
+// SecondaryYield is a rare extra item that can turn up alongside a
+// resource's main yield.
+type SecondaryYield struct {
+	Item   ItemID
+	Chance float32
+}
+
+// Resource is one tier of a gatherable tile: a kind of tree, rock or fishing
+// spot. Success chance scales linearly from LowChance at level 1 to
+// HighChance at level 99.
+type Resource struct {
+	Name          string
+	Level         int
+	XP            float64
+	Item          ItemID
+	LowChance     float32
+	HighChance    float32
+	DepleteChance float32 // chance each item used the resource up
+	Secondary     []SecondaryYield
+	Tint          rl.Color
+	Examine       string
+}
+
+var birdNest = []SecondaryYield{{"bird_nest", 0.01}}
+
+var gems = []SecondaryYield{
+	{"uncut_sapphire", 0.004},
+	{"uncut_emerald", 0.002},
+	{"uncut_ruby", 0.001},
+	{"uncut_diamond", 0.0005},
+}
+
+var resources = map[int]Resource{
+	ResourceTree:        {"Tree", 1, 25, "logs", 0.25, 0.78, 0.125, birdNest, rl.White, "A commonly found tree."},
+	ResourceOak:         {"Oak tree", 15, 37.5, "oak_logs", 0.125, 0.39, 0.125, birdNest, rl.Beige, "A beautiful old oak."},
+	ResourceWillow:      {"Willow tree", 30, 67.5, "willow_logs", 0.0625, 0.2, 0.125, birdNest, rl.Lime, "A droopy tree."},
+	ResourceCopper:      {"Copper rocks", 1, 17.5, "copper_ore", 0.39, 1, 1, gems, rl.Orange, "A rocky outcrop with copper in it."},
+	ResourceTin:         {"Tin rocks", 1, 17.5, "tin_ore", 0.39, 1, 1, gems, rl.LightGray, "A rocky outcrop with tin in it."},
+	ResourceIron:        {"Iron rocks", 15, 35, "iron_ore", 0.375, 1, 1, gems, rl.Brown, "A rocky outcrop with iron in it."},
+	ResourceCoal:        {"Coal rocks", 30, 50, "coal", 0.0625, 0.39, 1, gems, rl.DarkGray, "A rocky outcrop with coal in it."},
+	ResourceFishingSpot: {"Fishing spot", 1, 10, "raw_shrimps", 0.4, 0.8, 0, nil, rl.White, "I can see fish swimming in the water."},
+}
+
+// defaultResource is what a tile gets when it is set to a type without
+// saying which tier. Water has none until a fishing spot moves in.
+var defaultResource = map[int]int{
+	TileTree: ResourceTree,
+	TileRock: ResourceCopper,
+}
+
+// resourceSpawns weights which tier generated trees and rocks get.
+var resourceSpawns = map[int][]struct {
+	Resource int
+	Weight   float64
+}{
+	TileTree: {{ResourceTree, 0.6}, {ResourceOak, 0.25}, {ResourceWillow, 0.15}},
+	TileRock: {{ResourceCopper, 0.35}, {ResourceTin, 0.35}, {ResourceIron, 0.2}, {ResourceCoal, 0.1}},
+}
+
+func randomResource(tileType int) int {
+	r := sim.Rand.Float64()
+	for _, spawn := range resourceSpawns[tileType] {
+		if r < spawn.Weight {
+			return spawn.Resource
+		}
+		r -= spawn.Weight
+	}
+	return defaultResource[tileType]
+}
+
+// SuccessChance is the chance one attempt at this resource succeeds for
+// someone with the given level, before any tool bonus.
+func (r Resource) SuccessChance(level int) float32 {
+	level = min(max(level, 1), MaxLevel)
+	return r.LowChance + (r.HighChance-r.LowChance)*float32(level-1)/float32(MaxLevel-1)
+}
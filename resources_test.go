@@ -0,0 +1,59 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestSuccessChance(t *testing.T) {
+	tests := []struct {
+		resource int
+		level    int
+		want     float32
+	}{
+		{ResourceTree, 1, 0.25},
+		{ResourceTree, 50, 0.515},
+		{ResourceTree, 99, 0.78},
+		{ResourceTree, 0, 0.25},
+		{ResourceTree, 120, 0.78},
+		{ResourceOak, 15, 0.125 + 0.265*14/98},
+		{ResourceCopper, 99, 1},
+		{ResourceCoal, 30, 0.0625 + 0.3275*29/98},
+	}
+	for _, tt := range tests {
+		r := resources[tt.resource]
+		if got := r.SuccessChance(tt.level); math.Abs(float64(got-tt.want)) > 1e-6 {
+			t.Errorf("%s at level %d: %v, want %v", r.Name, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestGatherNeedsTheTiersLevel(t *testing.T) {
+	loadItems(t)
+
+	tests := []struct {
+		resource int
+		level    int
+		want     bool
+	}{
+		{ResourceTree, 1, true},
+		{ResourceOak, 14, false},
+		{ResourceOak, 15, true},
+		{ResourceWillow, 29, false},
+		{ResourceWillow, 30, true},
+	}
+	for _, tt := range tests {
+		m := NewMap(3, 1)
+		m.SetTile(1, 0, TileTree)
+		m.GetTile(1, 0).Resource = tt.resource
+		p := NewPlayer(0, 0, m, rl.Texture2D{}, rl.Texture2D{})
+		p.Skills.AddXP(SkillWoodcutting, XPForLevel(tt.level))
+		p.Inventory.Add(ItemSlot{ID: "bronze_axe", Count: 1})
+
+		if got := NewGatherAction(&p, Point{1, 0}).Start(); got != tt.want {
+			t.Errorf("%s at level %d: Start = %v, want %v", resources[tt.resource].Name, tt.level, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	MaxRunEnergy = float32(100)
+	// runSpeedMultiplier is how much faster running is than walking.
+	runSpeedMultiplier = float32(2)
+	// runDrainRate is energy lost per second of running with nothing
+	// equipped. Every runWeightScale of equipped weight adds the base rate
+	// again.
+	runDrainRate   = float32(4)
+	runWeightScale = float32(10)
+	// runRegenRate is energy regained per second while walking or idle.
+	runRegenRate = float32(1.5)
+
+	runOrbRadius = 18
+)
+
+var runOrbCenter = rl.NewVector2(ScreenWidth-40, ScreenHeight-40)
+
+// MoveSpeed is the player's current speed in pixels per second.
+func (p *Player) MoveSpeed() float32 {
+	if p.Running && p.RunEnergy > 0 {
+		return p.Speed * runSpeedMultiplier
+	}
+	return p.Speed
+}
+
+// RunDrainRate is how much energy a second of running costs with the
+// player's current equipment.
+func (p *Player) RunDrainRate() float32 {
+	return runDrainRate * (1 + p.Equipment.Weight()/runWeightScale)
+}
+
+// ToggleRun switches between walking and running. Running can't be turned on
+// with an empty meter.
+func (p *Player) ToggleRun() {
+	if !p.Running && p.RunEnergy <= 0 {
+		showMessage("You're too tired to run.")
+		return
+	}
+	p.Running = !p.Running
+}
+
+// updateRunEnergy drains energy for a step spent running and regenerates it
+// otherwise. The player drops back to walking when the meter runs out.
+func (p *Player) updateRunEnergy(t Tick, moved bool) {
+	if moved && p.Running {
+		p.RunEnergy -= p.RunDrainRate() * t.Dt
+		if p.RunEnergy <= 0 {
+			p.RunEnergy = 0
+			p.Running = false
+		}
+		return
+	}
+	p.RunEnergy = min(p.RunEnergy+runRegenRate*t.Dt, MaxRunEnergy)
+}
+
+func (p *Player) DrawRunOrb() {
+	fill := rl.Gray
+	if p.Running {
+		fill = rl.Gold
+	}
+
+	rl.DrawCircleV(runOrbCenter, runOrbRadius, rl.DarkGray)
+	rl.DrawCircleSector(runOrbCenter, runOrbRadius-2, -90, -90+360*p.RunEnergy/MaxRunEnergy, 32, fill)
+	rl.DrawCircleLinesV(runOrbCenter, runOrbRadius, rl.Black)
+
+	text := fmt.Sprintf("%d", int(p.RunEnergy))
+	textWidth := rl.MeasureText(text, 16)
+	rl.DrawText(text, int32(runOrbCenter.X)-textWidth/2, int32(runOrbCenter.Y)-8, 16, rl.Black)
+}
+
+// CheckRunOrbClick toggles running when the orb is clicked and reports
+// whether the mouse is over it, so the click doesn't also walk.
+func (p *Player) CheckRunOrbClick() bool {
+	if !rl.CheckCollisionPointCircle(rl.GetMousePosition(), runOrbCenter, runOrbRadius) {
+		return false
+	}
+	if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		p.ToggleRun()
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestRunEnergy(t *testing.T) {
+	loadItems(t)
+
+	tests := []struct {
+		name    string
+		running bool
+		moved   bool
+		weapon  ItemID
+		energy  float32
+		want    float32
+		still   bool // still running afterwards
+	}{
+		{"walking regenerates", false, true, "", 50, 51.5, false},
+		{"standing still while running regenerates", true, false, "", 50, 51.5, true},
+		{"running drains", true, true, "", 50, 46, true},
+		{"weight drains faster", true, true, "bronze_pickaxe", 50, 45.12, true},
+		{"regen stops at the maximum", false, false, "", 99.5, MaxRunEnergy, false},
+		{"running out drops to walking", true, true, "", 3, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPlayer(0, 0, NewMap(1, 1), rl.Texture2D{}, rl.Texture2D{})
+			p.Running, p.RunEnergy = tt.running, tt.energy
+			if tt.weapon != "" {
+				p.Equipment.Equip(SlotWeapon, ItemSlot{ID: tt.weapon, Count: 1})
+			}
+
+			p.updateRunEnergy(Tick{Dt: 1}, tt.moved)
+			if math.Abs(float64(p.RunEnergy-tt.want)) > 1e-4 {
+				t.Errorf("RunEnergy = %v, want %v", p.RunEnergy, tt.want)
+			}
+			if p.Running != tt.still {
+				t.Errorf("Running = %v, want %v", p.Running, tt.still)
+			}
+		})
+	}
+}
+
+func TestMoveSpeed(t *testing.T) {
+	p := NewPlayer(0, 0, NewMap(1, 1), rl.Texture2D{}, rl.Texture2D{})
+	walk := p.MoveSpeed()
+
+	p.ToggleRun()
+	if !p.Running || p.MoveSpeed() != walk*runSpeedMultiplier {
+		t.Fatalf("running at %v, walking at %v", p.MoveSpeed(), walk)
+	}
+
+	p.ToggleRun()
+	p.RunEnergy = 0
+	p.ToggleRun()
+	if p.Running || p.MoveSpeed() != walk {
+		t.Fatal("started running with an empty meter")
+	}
+}
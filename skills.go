@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+type Skill int
+
+const (
+	SkillAttack Skill = iota
+	SkillStrength
+	SkillDefence
+	SkillRanged
+	SkillHitpoints
+	SkillWoodcutting
+	SkillMining
+	SkillFishing
+	SkillCooking
+	SkillSmithing
+	SkillFiremaking
+	SkillFletching
+	SkillAgility
+	SkillFarming
+	skillCount
+)
+
+var skillNames = [skillCount]string{
+	SkillAttack:      "Attack",
+	SkillStrength:    "Strength",
+	SkillDefence:     "Defence",
+	SkillRanged:      "Ranged",
+	SkillHitpoints:   "Hitpoints",
+	SkillWoodcutting: "Woodcutting",
+	SkillMining:      "Mining",
+	SkillFishing:     "Fishing",
+	SkillCooking:     "Cooking",
+	SkillSmithing:    "Smithing",
+	SkillFiremaking:  "Firemaking",
+	SkillFletching:   "Fletching",
+	SkillAgility:     "Agility",
+	SkillFarming:     "Farming",
+}
+
+func (s Skill) String() string {
+	return skillNames[s]
+}
+
+const (
+	MaxLevel = 99
+	// startingHitpoints is the Hitpoints level a new character starts at.
+	startingHitpoints = 10
+)
+
+// xpTable[l] is the experience needed to reach level l, using the RuneScape
+// curve: each level costs a little more than the last, roughly doubling
+// every seven levels.
+var xpTable = func() [MaxLevel + 1]float64 {
+	var table [MaxLevel + 1]float64
+	points := 0.0
+	for level := 2; level <= MaxLevel; level++ {
+		l := float64(level - 1)
+		points += math.Floor(l + 300*math.Pow(2, l/7))
+		table[level] = math.Floor(points / 4)
+	}
+	return table
+}()
+
+func XPForLevel(level int) float64 {
+	if level <= 1 {
+		return 0
+	}
+	return xpTable[min(level, MaxLevel)]
+}
+
+func LevelForXP(xp float64) int {
+	level := 1
+	for level < MaxLevel && xp >= xpTable[level+1] {
+		level++
+	}
+	return level
+}
+
+// Skills holds a character's experience in every skill. Levels are always
+// worked out from experience, never stored.
+type Skills struct {
+	xp [skillCount]float64
+}
+
+func NewSkills() *Skills {
+	s := &Skills{}
+	s.xp[SkillHitpoints] = XPForLevel(startingHitpoints)
+	return s
+}
+
+func (s *Skills) XP(skill Skill) float64 {
+	return s.xp[skill]
+}
+
+func (s *Skills) Level(skill Skill) int {
+	return LevelForXP(s.xp[skill])
+}
+
+// AddXP grants experience and returns the skill's new level if it went up,
+// or 0 otherwise.
+func (s *Skills) AddXP(skill Skill, amount float64) int {
+	before := s.Level(skill)
+	s.xp[skill] += amount
+	if after := s.Level(skill); after > before {
+		return after
+	}
+	return 0
+}
+
+// GainXP grants the player experience and announces any level-up. Hitpoints
+// levels also raise maximum health.
+func (p *Player) GainXP(skill Skill, amount float64) {
+	level := p.Skills.AddXP(skill, amount)
+	if level == 0 {
+		return
+	}
+
+	if skill == SkillHitpoints {
+		p.MaxHealth = level * 10
+	}
+	showMessage(fmt.Sprintf("Congratulations! Your %s level is now %d.", skill, level))
+}
+
+const (
+	skillBoxWidth  = 100
+	skillBoxHeight = 24
+	skillColumns   = 3
+)
+
+func skillRect(x, y int, skill Skill) rl.Rectangle {
+	i := int(skill)
+	cx := x + (i%skillColumns)*(skillBoxWidth+4)
+	cy := y + (i/skillColumns)*(skillBoxHeight+4)
+	return rl.NewRectangle(float32(cx), float32(cy), skillBoxWidth, skillBoxHeight)
+}
+
+func (p *Player) DrawSkills(x, y int) {
+	for skill := Skill(0); skill < skillCount; skill++ {
+		rect := skillRect(x, y, skill)
+		rl.DrawRectangleRec(rect, rl.LightGray)
+		rl.DrawRectangleLinesEx(rect, 1, rl.DarkGray)
+		rl.DrawText(fmt.Sprintf("%s %d", skill, p.Skills.Level(skill)), int32(rect.X+4), int32(rect.Y+5), 14, rl.Black)
+	}
+
+	hovered := p.GetHoveredSkill(x, y)
+	if hovered < 0 {
+		return
+	}
+
+	text := fmt.Sprintf("%s XP: %d", hovered, int(p.Skills.XP(hovered)))
+	if level := p.Skills.Level(hovered); level < MaxLevel {
+		text += fmt.Sprintf("\nNext level: %d", int(XPForLevel(level+1)-p.Skills.XP(hovered)))
+	}
+	mouse := rl.GetMousePosition()
+	rl.DrawRectangleRec(rl.NewRectangle(mouse.X, mouse.Y-44, 160, 40), rl.Fade(rl.Black, 0.8))
+	rl.DrawText(text, int32(mouse.X+4), int32(mouse.Y-40), 16, rl.White)
+}
+
+// GetHoveredSkill returns the skill under the mouse in the skills panel, or
+// -1.
+func (p *Player) GetHoveredSkill(x, y int) Skill {
+	mouse := rl.GetMousePosition()
+	for skill := Skill(0); skill < skillCount; skill++ {
+		if rl.CheckCollisionPointRec(mouse, skillRect(x, y, skill)) {
+			return skill
+		}
+	}
+	return -1
+}
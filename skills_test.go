@@ -0,0 +1,77 @@
+package main
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestXPTable(t *testing.T) {
+	// Figures from the RuneScape experience table.
+	tests := []struct {
+		level int
+		xp    float64
+	}{
+		{1, 0},
+		{2, 83},
+		{3, 174},
+		{10, 1154},
+		{50, 101333},
+		{92, 6517253},
+		{99, 13034431},
+	}
+	for _, tt := range tests {
+		if got := XPForLevel(tt.level); got != tt.xp {
+			t.Errorf("XPForLevel(%d) = %v, want %v", tt.level, got, tt.xp)
+		}
+		if got := LevelForXP(tt.xp); got != tt.level {
+			t.Errorf("LevelForXP(%v) = %d, want %d", tt.xp, got, tt.level)
+		}
+		if tt.level > 1 {
+			if got := LevelForXP(tt.xp - 1); got != tt.level-1 {
+				t.Errorf("LevelForXP(%v) = %d, want %d", tt.xp-1, got, tt.level-1)
+			}
+		}
+	}
+	if got := LevelForXP(200_000_000); got != MaxLevel {
+		t.Errorf("LevelForXP past the table = %d, want %d", got, MaxLevel)
+	}
+}
+
+func TestAddXP(t *testing.T) {
+	s := NewSkills()
+	if got := s.Level(SkillHitpoints); got != startingHitpoints {
+		t.Fatalf("new character has Hitpoints %d", got)
+	}
+
+	tests := []struct {
+		amount float64
+		level  int // AddXP's result
+	}{
+		{50, 0},
+		{32.9, 0},
+		{0.1, 2},
+		{1154 - 83, 10},
+		{0, 0},
+		{XPForLevel(MaxLevel), MaxLevel},
+		{1e6, 0},
+	}
+	for _, tt := range tests {
+		before := s.XP(SkillMining)
+		if got := s.AddXP(SkillMining, tt.amount); got != tt.level {
+			t.Errorf("AddXP(%v) from %v xp = %d, want %d", tt.amount, before, got, tt.level)
+		}
+	}
+}
+
+func TestGainXPRaisesMaxHealth(t *testing.T) {
+	p := NewPlayer(0, 0, NewMap(1, 1), rl.Texture2D{}, rl.Texture2D{})
+	p.GainXP(SkillHitpoints, XPForLevel(12)-p.Skills.XP(SkillHitpoints))
+	if p.MaxHealth != 120 {
+		t.Fatalf("MaxHealth = %d at Hitpoints 12", p.MaxHealth)
+	}
+	p.GainXP(SkillAttack, XPForLevel(30))
+	if p.MaxHealth != 120 {
+		t.Fatalf("an Attack level changed MaxHealth to %d", p.MaxHealth)
+	}
+}
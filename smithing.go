@@ -0,0 +1,64 @@
+package main
+
+const (
+	smeltTicks = 4
+	smithTicks = 4
+)
+
+func smelt(bar ItemID, level int, xp float64, inputs ...ItemSlot) Recipe {
+	return Recipe{
+		Inputs:  inputs,
+		Output:  ItemSlot{ID: bar, Count: 1},
+		Skill:   SkillSmithing,
+		XP:      xp,
+		Level:   level,
+		Station: StationFurnace,
+		Ticks:   smeltTicks,
+	}
+}
+
+func smith(output ItemID, bars int, bar ItemID, level int, xp float64) Recipe {
+	return Recipe{
+		Inputs:  []ItemSlot{{ID: bar, Count: bars}},
+		Output:  ItemSlot{ID: output, Count: 1},
+		Skill:   SkillSmithing,
+		XP:      xp,
+		Level:   level,
+		Station: StationAnvil,
+		Tools:   []ItemID{"hammer"},
+		Ticks:   smithTicks,
+	}
+}
+
+// arrowtips smiths a batch of 15 arrowtips from one bar.
+func arrowtips(metal string, level int, xp float64) Recipe {
+	r := smith(ItemID(metal+"_arrowtips"), 1, ItemID(metal+"_bar"), level, xp)
+	r.Output.Count = 15
+	return r
+}
+
+var ironSmelt = func() Recipe {
+	r := smelt("iron_bar", 15, 12.5, ItemSlot{ID: "iron_ore", Count: 1})
+	r.FailChance = 0.5
+	r.FailMessage = "The ore is too impure and you fail to refine it."
+	return r
+}()
+
+var smithingRecipes = []Recipe{
+	smelt("bronze_bar", 1, 6.25, ItemSlot{ID: "copper_ore", Count: 1}, ItemSlot{ID: "tin_ore", Count: 1}),
+	ironSmelt,
+	smelt("steel_bar", 30, 17.5, ItemSlot{ID: "iron_ore", Count: 1}, ItemSlot{ID: "coal", Count: 2}),
+
+	smith("bronze_dagger", 1, "bronze_bar", 1, 12.5),
+	smith("bronze_sword", 1, "bronze_bar", 4, 12.5),
+	smith("bronze_full_helm", 2, "bronze_bar", 7, 25),
+	smith("bronze_sq_shield", 2, "bronze_bar", 8, 25),
+	smith("bronze_platelegs", 3, "bronze_bar", 16, 37.5),
+	smith("bronze_platebody", 5, "bronze_bar", 18, 62.5),
+	arrowtips("bronze", 5, 12.5),
+	smith("iron_sword", 1, "iron_bar", 19, 25),
+	smith("iron_platebody", 5, "iron_bar", 33, 125),
+	arrowtips("iron", 20, 25),
+	smith("steel_sword", 1, "steel_bar", 34, 37.5),
+	arrowtips("steel", 35, 37.5),
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func recipeFor(t *testing.T, output ItemID) Recipe {
+	t.Helper()
+	for _, r := range Recipes {
+		if r.Output.ID == output {
+			return r
+		}
+	}
+	t.Fatalf("no recipe makes %s", output)
+	return Recipe{}
+}
+
+// smithingPlayer stands next to an anvil at 1,0 with the given Smithing level
+// and items.
+func smithingPlayer(level int, items ...ItemSlot) *Player {
+	m := NewMap(3, 1)
+	m.SetTile(1, 0, TileAnvil)
+	p := NewPlayer(0, 0, m, rl.Texture2D{}, rl.Texture2D{})
+	p.Skills.AddXP(SkillSmithing, XPForLevel(level))
+	for _, item := range items {
+		p.Inventory.Add(item)
+	}
+	return &p
+}
+
+func TestCanMake(t *testing.T) {
+	loadItems(t)
+	hammer := ItemSlot{ID: "hammer", Count: 1}
+	bars := func(n int) ItemSlot { return ItemSlot{ID: "bronze_bar", Count: n} }
+
+	tests := []struct {
+		output  ItemID
+		level   int
+		items   []ItemSlot
+		problem string
+	}{
+		{"bronze_dagger", 1, []ItemSlot{hammer, bars(1)}, ""},
+		{"bronze_dagger", 1, []ItemSlot{bars(1)}, "You need a hammer to make that."},
+		{"bronze_dagger", 1, []ItemSlot{hammer}, "You don't have the materials to make that."},
+		{"bronze_platebody", 18, []ItemSlot{hammer, bars(4)}, "You don't have the materials to make that."},
+		{"bronze_platebody", 18, []ItemSlot{hammer, bars(5)}, ""},
+		{"bronze_platebody", 17, []ItemSlot{hammer, bars(5)}, "You need a Smithing level of 18 to make that."},
+		{"steel_bar", 30, []ItemSlot{{ID: "iron_ore", Count: 1}, {ID: "coal", Count: 2}}, ""},
+		{"steel_bar", 30, []ItemSlot{{ID: "iron_ore", Count: 1}, {ID: "coal", Count: 1}}, "You don't have the materials to make that."},
+	}
+	for _, tt := range tests {
+		p := smithingPlayer(tt.level, tt.items...)
+		ok, problem := p.CanMake(recipeFor(t, tt.output))
+		if ok != (tt.problem == "") || problem != tt.problem {
+			t.Errorf("%s at level %d with %v: %v, %q; want %q", tt.output, tt.level, tt.items, ok, problem, tt.problem)
+		}
+	}
+}
+
+func TestCraftActionQuantity(t *testing.T) {
+	loadItems(t)
+
+	tests := []struct {
+		name  string
+		bars  int
+		count int
+		made  int
+	}{
+		{"one", 5, 1, 1},
+		{"make five", 10, 5, 5},
+		{"make X of more than there are bars for", 3, 10, 3},
+		{"make all", 7, MakeAll, 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := smithingPlayer(1, ItemSlot{ID: "hammer", Count: 1}, ItemSlot{ID: "bronze_bar", Count: tt.bars})
+			p.Actions.Push(NewCraftAction(p, recipeFor(t, "bronze_dagger"), tt.count, Point{1, 0}))
+
+			for range 1000 {
+				if p.Actions.Idle() {
+					break
+				}
+				p.Actions.Update(Tick{Dt: MoveStep, Game: true})
+			}
+			if !p.Actions.Idle() {
+				t.Fatal("still smithing after 1000 ticks")
+			}
+			if got := p.Inventory.Count("bronze_dagger"); got != tt.made {
+				t.Errorf("made %d daggers, want %d", got, tt.made)
+			}
+			if got := p.Inventory.Count("bronze_bar"); got != tt.bars-tt.made {
+				t.Errorf("%d bars left, want %d", got, tt.bars-tt.made)
+			}
+		})
+	}
+}
+
+func TestCraftActionNeedsStation(t *testing.T) {
+	loadItems(t)
+	p := smithingPlayer(1, ItemSlot{ID: "hammer", Count: 1}, ItemSlot{ID: "bronze_bar", Count: 1})
+	p.Map.SetTile(1, 0, TileGrass)
+	if NewCraftAction(p, recipeFor(t, "bronze_dagger"), 1, Point{1, 0}).Start() {
+		t.Fatal("smithed without an anvil")
+	}
+}
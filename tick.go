@@ -0,0 +1,74 @@
+package main
+
+import (
+	"math/rand"
+	"time"
+)
+
+const (
+	// MoveStep is the fixed simulation step in seconds. Movement advances
+	// once per step, whatever the frame rate.
+	MoveStep = float32(0.05)
+	// StepsPerGameTick makes the RuneScape-style 600 ms game tick that
+	// actions, gathering and combat count in.
+	StepsPerGameTick = 12
+	// maxFrameTime caps how much simulation one slow frame can owe, so a
+	// stall does not turn into a burst of hundreds of catch-up steps.
+	maxFrameTime = float32(0.25)
+)
+
+// Tick is one fixed simulation step handed to everything that simulates.
+// Feeding the same ticks in the same order, with the same Sim, always gives
+// the same result.
+type Tick struct {
+	N    uint64  // steps since the clock started
+	Dt   float32 // seconds covered by this step, always MoveStep
+	Game bool    // this step also advances the 600 ms game tick
+}
+
+// Clock turns variable render frame times into a whole number of fixed
+// simulation steps, carrying the remainder over to the next frame.
+type Clock struct {
+	accumulator float32
+	steps       uint64
+}
+
+// Advance adds a frame's worth of time and returns how many steps are due.
+func (c *Clock) Advance(frameTime float32) int {
+	c.accumulator += min(frameTime, maxFrameTime)
+	n := int(c.accumulator / MoveStep)
+	c.accumulator -= float32(n) * MoveStep
+	return n
+}
+
+// Next returns the next step to simulate.
+func (c *Clock) Next() Tick {
+	c.steps++
+	return Tick{
+		N:    c.steps,
+		Dt:   MoveStep,
+		Game: c.steps%StepsPerGameTick == 0,
+	}
+}
+
+// Alpha is how far the renderer is between the last simulated state and the
+// next one, for interpolating positions.
+func (c *Clock) Alpha() float32 {
+	return c.accumulator / MoveStep
+}
+
+// Sim is where the simulation gets its chance rolls and the time of day.
+// Nothing that simulates uses math/rand or time.Now directly, so a seeded
+// Rand and a fixed Now make a run repeatable.
+type Sim struct {
+	Rand *rand.Rand
+	Now  func() time.Time
+}
+
+func NewSim(seed int64, now func() time.Time) Sim {
+	return Sim{Rand: rand.New(rand.NewSource(seed)), Now: now}
+}
+
+// sim is seeded from the wall clock, so each session plays out differently.
+// Swap it out before generating the map to replay one.
+var sim = NewSim(time.Now().UnixNano(), time.Now)
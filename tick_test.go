@@ -0,0 +1,132 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestClockAdvance(t *testing.T) {
+	tests := []struct {
+		frame float32
+		steps int
+		alpha float32
+	}{
+		{0.02, 0, 0.4},
+		{0.04, 1, 0.2},
+		{0.07, 1, 0.6},
+		{0.016, 0, 0.92},
+		// A stall only owes maxFrameTime, not the whole second.
+		{1, 5, 0.92},
+		{0, 0, 0.92},
+	}
+
+	var c Clock
+	for i, tt := range tests {
+		if got := c.Advance(tt.frame); got != tt.steps {
+			t.Fatalf("frame %d: Advance(%v) = %d steps, want %d", i, tt.frame, got, tt.steps)
+		}
+		if got := c.Alpha(); math.Abs(float64(got-tt.alpha)) > 1e-4 {
+			t.Fatalf("frame %d: Alpha = %v, want %v", i, got, tt.alpha)
+		}
+	}
+}
+
+func TestClockNext(t *testing.T) {
+	var c Clock
+	for n := uint64(1); n <= 5*StepsPerGameTick; n++ {
+		tick := c.Next()
+		if tick.N != n || tick.Dt != MoveStep {
+			t.Fatalf("step %d: got %+v", n, tick)
+		}
+		if want := n%StepsPerGameTick == 0; tick.Game != want {
+			t.Fatalf("step %d: Game = %v, want %v", n, tick.Game, want)
+		}
+	}
+}
+
+// newWorld sets up the globals Simulate runs on the way main does, with sim
+// seeded from seed and stopped at a fixed time.
+func newWorld(seed int64) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	sim = NewSim(seed, func() time.Time { return now })
+
+	gameMap = NewMap(20, 15)
+	gameMap.Generate(0.1, 0.05, 0.05)
+	chaseField = NewFlowField(gameMap, false)
+	fleeField = NewFlowField(gameMap, true)
+
+	player = NewPlayer(
+		float32((SpawnX+SpawnWidth/2)*TileSize),
+		float32((SpawnY+SpawnHeight/2)*TileSize),
+		gameMap, rl.Texture2D{}, rl.Texture2D{},
+	)
+	enemies = nil
+	spawnEnemy(Enemy{
+		Pos:        rl.NewVector2(100, 100),
+		Health:     50,
+		MaxHealth:  50,
+		Name:       "Slime",
+		Speed:      60,
+		AggroRange: 6,
+		LootTable: []LootEntry{
+			{Item: ItemSlot{ID: "coins", Count: 5}, Chance: 0.5},
+			{Item: ItemSlot{ID: "feather", Count: 15}, Chance: 0.5},
+		},
+	})
+	inCombat, currentEnemy = false, nil
+	fires, groundItems = nil, nil
+	clock = Clock{}
+}
+
+// worldState is everything a replay has to reproduce.
+type worldState struct {
+	PlayerPos   rl.Vector2
+	Health      int
+	Inventory   [28]ItemSlot
+	Enemies     []Enemy
+	GroundItems []GroundItem
+	Tiles       [][]Tile
+}
+
+func snapshot() worldState {
+	s := worldState{
+		PlayerPos: player.Pos,
+		Health:    player.Health,
+		Inventory: player.Inventory.Slots(),
+		Enemies:   append([]Enemy(nil), enemies...),
+		Tiles:     gameMap.Tiles,
+	}
+	for _, g := range groundItems {
+		s.GroundItems = append(s.GroundItems, *g)
+	}
+	return s
+}
+
+func TestSimulateReplays(t *testing.T) {
+	run := func() worldState {
+		newWorld(42)
+		// Walk off towards the slime so there is a fight to roll for.
+		player.MoveToTile(3, 3)
+		for range 20 {
+			for n := clock.Advance(0.1); n > 0; n-- {
+				Simulate(clock.Next())
+			}
+		}
+		for range 3000 {
+			Simulate(clock.Next())
+		}
+		return snapshot()
+	}
+
+	first, second := run(), run()
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("same seed and clock gave different worlds:\n%+v\n%+v", first, second)
+	}
+	if first.Health == 100 && len(first.Enemies) == 1 && first.Enemies[0].Health == 50 {
+		t.Fatal("nothing happened, so the replay proves nothing")
+	}
+}
@@ -8,16 +8,66 @@ var tileRects = map[int]rl.Rectangle{
 	TileRock:  {X: 32, Y: 576, Width: TileSize, Height: TileSize},
 }
 
+// TileDef is what the player is told about a kind of tile.
+type TileDef struct {
+	Name    string
+	Examine string
+}
+
+var tileDefs = map[int]TileDef{
+	TileGrass:     {"Grass", ""},
+	TileTree:      {"Tree", "A commonly found tree."},
+	TileRock:      {"Rocks", "A rocky outcrop."},
+	TileWater:     {"Water", "It looks cold and deep."},
+	TileFire:      {"Fire", "Hot!"},
+	TileRange:     {"Range", "A hot surface for cooking on."},
+	TileFurnace:   {"Furnace", "Hot enough to smelt ore into bars."},
+	TileAnvil:     {"Anvil", "Used for hammering metal into shape."},
+	TileFarmPatch: {"Farming patch", "A patch of soil for growing crops."},
+	TileWall:      {"Wall", "A sturdy stone wall."},
+	TileBankBooth: {"Bank booth", "The bank teller will serve you from here."},
+}
+
 type Tile struct {
-	Type int
+	Type     int
+	Resource int    // which tree, rock or fishing spot this is, if any
+	Patch    *Patch // growth state, on farming patches only
 }
 
 func (t Tile) IsWalkable() bool {
 	return t.Type == TileGrass
 }
 
+// MoveCost is what stepping onto the tile costs a pathfinder. All walkable
+// terrain costs 1 today; anything added later must stay at or above 1 so the
+// Manhattan heuristic remains admissible.
+func (t Tile) MoveCost() float64 {
+	return 1
+}
+
 func (t Tile) IsGatherable() bool {
-	return t.Type == TileTree || t.Type == TileWater || t.Type == TileRock
+	return t.Resource != ResourceNone
+}
+
+// IsPatch reports whether the tile is a farming patch.
+func (t Tile) IsPatch() bool {
+	return t.Patch != nil
+}
+
+// IsStation reports whether the tile is a furnace, anvil or other place
+// recipes get made.
+func (t Tile) IsStation() bool {
+	return stationAt(t.Type) != StationNone
+}
+
+// IsBank reports whether the bank can be opened at the tile.
+func (t Tile) IsBank() bool {
+	return t.Type == TileBankBooth
+}
+
+// IsCookingSource reports whether food can be cooked on the tile.
+func (t Tile) IsCookingSource() bool {
+	return t.Type == TileRange || t.Type == TileFire
 }
 
 func (t Tile) Draw(texture rl.Texture2D, x, y int32) {
@@ -41,7 +91,61 @@ func (t Tile) Draw(texture rl.Texture2D, x, y int32) {
 			Width:  TileSize,
 			Height: TileSize,
 		}
+		tint := rl.White
+		if res, ok := resources[t.Resource]; ok {
+			tint = res.Tint
+		}
+		rl.DrawTexturePro(texture, src, dest, rl.Vector2{X: 0, Y: 0}, 0, tint)
+	case TileRange:
+		rl.DrawRectangle(x*TileSize, y*TileSize, TileSize, TileSize, rl.DarkGray)
+		rl.DrawRectangle(x*TileSize+4, y*TileSize+4, TileSize-8, 8, rl.Black)
+		rl.DrawRectangle(x*TileSize+8, y*TileSize+18, TileSize-16, 10, rl.Orange)
+	case TileFurnace:
+		rl.DrawRectangle(x*TileSize, y*TileSize, TileSize, TileSize, rl.Brown)
+		rl.DrawRectangle(x*TileSize+8, y*TileSize+12, TileSize-16, TileSize-16, rl.Black)
+		rl.DrawRectangle(x*TileSize+11, y*TileSize+18, TileSize-22, TileSize-24, rl.Orange)
+	case TileAnvil:
+		src := tileRects[TileGrass]
+		dest := rl.Rectangle{
+			X:      float32(x * TileSize),
+			Y:      float32(y * TileSize),
+			Width:  TileSize,
+			Height: TileSize,
+		}
 		rl.DrawTexturePro(texture, src, dest, rl.Vector2{X: 0, Y: 0}, 0, rl.White)
+		rl.DrawRectangle(x*TileSize+4, y*TileSize+10, TileSize-8, 6, rl.DarkGray)
+		rl.DrawRectangle(x*TileSize+12, y*TileSize+16, 8, 8, rl.DarkGray)
+		rl.DrawRectangle(x*TileSize+8, y*TileSize+24, TileSize-16, 4, rl.DarkGray)
+	case TileWall:
+		rl.DrawRectangle(x*TileSize, y*TileSize, TileSize, TileSize, rl.Gray)
+		for row := int32(0); row < 4; row++ {
+			offset := (row % 2) * 8
+			rl.DrawLine(x*TileSize, y*TileSize+row*8, x*TileSize+TileSize, y*TileSize+row*8, rl.DarkGray)
+			rl.DrawLine(x*TileSize+offset+4, y*TileSize+row*8, x*TileSize+offset+4, y*TileSize+row*8+8, rl.DarkGray)
+			rl.DrawLine(x*TileSize+offset+20, y*TileSize+row*8, x*TileSize+offset+20, y*TileSize+row*8+8, rl.DarkGray)
+		}
+	case TileBankBooth:
+		rl.DrawRectangle(x*TileSize, y*TileSize, TileSize, TileSize, rl.DarkBrown)
+		rl.DrawRectangle(x*TileSize+2, y*TileSize+2, TileSize-4, 10, rl.Maroon)
+		rl.DrawRectangle(x*TileSize+6, y*TileSize+16, TileSize-12, TileSize-20, rl.Beige)
+		rl.DrawCircle(x*TileSize+TileSize/2, y*TileSize+22, 3, rl.Gold)
+	case TileFarmPatch:
+		rl.DrawRectangle(x*TileSize, y*TileSize, TileSize, TileSize, rl.Brown)
+		rl.DrawRectangleLines(x*TileSize, y*TileSize, TileSize, TileSize, rl.DarkBrown)
+		if t.Patch != nil {
+			t.Patch.Draw(x, y)
+		}
+	case TileFire:
+		src := tileRects[TileGrass]
+		dest := rl.Rectangle{
+			X:      float32(x * TileSize),
+			Y:      float32(y * TileSize),
+			Width:  TileSize,
+			Height: TileSize,
+		}
+		rl.DrawTexturePro(texture, src, dest, rl.Vector2{X: 0, Y: 0}, 0, rl.White)
+		rl.DrawCircle(x*TileSize+TileSize/2, y*TileSize+TileSize/2+4, 10, rl.Orange)
+		rl.DrawCircle(x*TileSize+TileSize/2, y*TileSize+TileSize/2+2, 6, rl.Yellow)
 	default:
 		src := tileRects[t.Type] // `tileRects` is your atlas frame map
 		dest := rl.Rectangle{
@@ -53,4 +157,9 @@ func (t Tile) Draw(texture rl.Texture2D, x, y int32) {
 		rl.DrawTexturePro(texture, src, dest, rl.Vector2{X: 0, Y: 0}, 0, rl.White)
 	}
 
+	if t.Resource == ResourceFishingSpot {
+		center := rl.NewVector2(float32(x*TileSize+TileSize/2), float32(y*TileSize+TileSize/2))
+		rl.DrawCircleLinesV(center, 6, rl.SkyBlue)
+		rl.DrawCircleLinesV(center, 11, rl.Fade(rl.SkyBlue, 0.6))
+	}
 }
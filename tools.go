@@ -0,0 +1,99 @@
+package main
+
+import "fmt"
+
+type ToolKind string
+
+const (
+	ToolAxe        ToolKind = "axe"
+	ToolPickaxe    ToolKind = "pickaxe"
+	ToolFishingRod ToolKind = "fishing rod"
+	ToolNet        ToolKind = "net"
+)
+
+// Tool describes a gathering tool. Better tiers need a higher level but take
+// ticks off every attempt and make each one more likely to succeed.
+type Tool struct {
+	Item         ItemID
+	Kind         ToolKind
+	Skill        Skill
+	Level        int     // skill level needed to use it
+	Tier         int     // 0 for bronze up to 5 for rune
+	TickBonus    int     // game ticks saved per attempt
+	SuccessBonus float32 // added to the resource's success chance
+}
+
+var tools = []Tool{
+	{"bronze_axe", ToolAxe, SkillWoodcutting, 1, 0, 0, 0},
+	{"iron_axe", ToolAxe, SkillWoodcutting, 1, 1, 0, 0.05},
+	{"steel_axe", ToolAxe, SkillWoodcutting, 6, 2, 1, 0.1},
+	{"mithril_axe", ToolAxe, SkillWoodcutting, 21, 3, 1, 0.15},
+	{"adamant_axe", ToolAxe, SkillWoodcutting, 31, 4, 1, 0.2},
+	{"rune_axe", ToolAxe, SkillWoodcutting, 41, 5, 2, 0.25},
+
+	{"bronze_pickaxe", ToolPickaxe, SkillMining, 1, 0, 0, 0},
+	{"iron_pickaxe", ToolPickaxe, SkillMining, 1, 1, 0, 0.05},
+	{"steel_pickaxe", ToolPickaxe, SkillMining, 6, 2, 1, 0.1},
+	{"mithril_pickaxe", ToolPickaxe, SkillMining, 21, 3, 1, 0.15},
+	{"adamant_pickaxe", ToolPickaxe, SkillMining, 31, 4, 1, 0.2},
+	{"rune_pickaxe", ToolPickaxe, SkillMining, 41, 5, 2, 0.25},
+
+	{"fishing_rod", ToolFishingRod, SkillFishing, 1, 0, 0, 0},
+	{"small_fishing_net", ToolNet, SkillFishing, 1, 0, 0, 0},
+}
+
+func toolByItem(id ItemID) *Tool {
+	for i := range tools {
+		if tools[i].Item == id {
+			return &tools[i]
+		}
+	}
+	return nil
+}
+
+// BestTool returns the highest tier tool of any of the given kinds that the
+// player is carrying or wielding and has the level to use. If there is none,
+// problem says why in words fit to show the player.
+func (p *Player) BestTool(kinds []ToolKind) (best *Tool, problem string) {
+	carried := p.Inventory.Slots()
+	candidates := append(carried[:], p.Equipment.Slots[SlotWeapon])
+
+	var tooHigh *Tool
+	for _, item := range candidates {
+		t := toolByItem(item.ID)
+		if t == nil || !hasToolKind(kinds, t.Kind) {
+			continue
+		}
+		if p.Skills.Level(t.Skill) < t.Level {
+			if tooHigh == nil || t.Level < tooHigh.Level {
+				tooHigh = t
+			}
+			continue
+		}
+		if best == nil || t.Tier > best.Tier {
+			best = t
+		}
+	}
+
+	switch {
+	case best != nil:
+		return best, ""
+	case tooHigh != nil:
+		return nil, fmt.Sprintf("You need a %s level of %d to use the %s.", tooHigh.Skill, tooHigh.Level, itemName(tooHigh.Item))
+	}
+
+	names := string(kinds[0])
+	for _, k := range kinds[1:] {
+		names += " or " + string(k)
+	}
+	return nil, fmt.Sprintf("You need %s %s to do that.", article(names), names)
+}
+
+func hasToolKind(kinds []ToolKind, kind ToolKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
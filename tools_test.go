@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestBestTool(t *testing.T) {
+	loadItems(t)
+	axes := []ToolKind{ToolAxe}
+
+	tests := []struct {
+		name    string
+		woodcut int
+		carried []ItemID
+		wielded ItemID
+		kinds   []ToolKind
+		want    ItemID
+		problem string
+	}{
+		{"no tool", 1, nil, "", axes, "", "You need an axe to do that."},
+		{"wrong kind", 1, []ItemID{"bronze_pickaxe"}, "", axes, "", "You need an axe to do that."},
+		{"carried", 1, []ItemID{"bronze_axe"}, "", axes, "bronze_axe", ""},
+		{"wielded", 1, nil, "iron_axe", axes, "iron_axe", ""},
+		{"highest tier wins", 41, []ItemID{"steel_axe", "rune_axe", "bronze_axe"}, "mithril_axe", axes, "rune_axe", ""},
+		{"skips tools above the level", 21, []ItemID{"rune_axe", "steel_axe"}, "", axes, "steel_axe", ""},
+		{"only tools above the level", 1, []ItemID{"rune_axe", "mithril_axe"}, "", axes, "", "You need a Woodcutting level of 21 to use the mithril axe."},
+		{"any of several kinds", 1, []ItemID{"small_fishing_net"}, "", []ToolKind{ToolFishingRod, ToolNet}, "small_fishing_net", ""},
+		{"names every kind", 1, nil, "", []ToolKind{ToolFishingRod, ToolNet}, "", "You need a fishing rod or net to do that."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPlayer(0, 0, NewMap(1, 1), rl.Texture2D{}, rl.Texture2D{})
+			p.Skills.AddXP(SkillWoodcutting, XPForLevel(tt.woodcut))
+			for _, id := range tt.carried {
+				p.Inventory.Add(ItemSlot{ID: id, Count: 1})
+			}
+			if tt.wielded != "" {
+				p.Equipment.Equip(SlotWeapon, ItemSlot{ID: tt.wielded, Count: 1})
+			}
+
+			tool, problem := p.BestTool(tt.kinds)
+			var got ItemID
+			if tool != nil {
+				got = tool.Item
+			}
+			if got != tt.want || problem != tt.problem {
+				t.Fatalf("BestTool = %q, %q; want %q, %q", got, problem, tt.want, tt.problem)
+			}
+		})
+	}
+}
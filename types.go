@@ -5,7 +5,14 @@ type Point struct {
 }
 
 type Recipe struct {
-	Name   string
-	Inputs []ItemSlot
-	Output ItemSlot
+	Inputs      []ItemSlot
+	Output      ItemSlot
+	Skill       Skill   // skill trained by crafting this
+	XP          float64 // experience per craft, 0 for none
+	Level       int     // Skill level needed
+	Station     Station // where it has to be made, if anywhere in particular
+	Tools       []ItemID
+	Ticks       int     // game ticks per item, 0 for instant
+	FailChance  float32 // chance the inputs are used up for nothing
+	FailMessage string
 }
@@ -0,0 +1,178 @@
+package main
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+// Handlers for "Use X on Y". Each is given the inventory slot of the item
+// being used, X, and whatever it was used on.
+type (
+	ItemOnItem  func(p *Player, used, target int)
+	ItemOnTile  func(p *Player, used int, pos Point)
+	ItemOnEnemy func(p *Player, used int, e *Enemy)
+)
+
+type itemPair struct{ Used, Target ItemID }
+
+type itemTile struct {
+	Used ItemID
+	Tile int
+}
+
+type itemEnemy struct {
+	Used  ItemID
+	Enemy string
+}
+
+// UseTable looks up what happens when one thing is used on another.
+type UseTable struct {
+	onItem  map[itemPair]ItemOnItem
+	onTile  map[itemTile]ItemOnTile
+	onEnemy map[itemEnemy]ItemOnEnemy
+}
+
+// uses is every "Use X on Y" in the game. Anything missing from it gets
+// "Nothing interesting happens."
+var uses = newUseTable()
+
+// Item registers h for using a on b. It works either way round; h is always
+// given a's slot first.
+func (u *UseTable) Item(a, b ItemID, h ItemOnItem) {
+	u.onItem[itemPair{a, b}] = h
+	if a != b {
+		u.onItem[itemPair{b, a}] = func(p *Player, used, target int) { h(p, target, used) }
+	}
+}
+
+// Tile registers h for using item on any tile of a type.
+func (u *UseTable) Tile(item ItemID, tileType int, h ItemOnTile) {
+	u.onTile[itemTile{item, tileType}] = h
+}
+
+// Enemy registers h for using item on enemies with the given name.
+func (u *UseTable) Enemy(item ItemID, name string, h ItemOnEnemy) {
+	u.onEnemy[itemEnemy{item, name}] = h
+}
+
+func nothingInteresting() {
+	showMessage("Nothing interesting happens.")
+}
+
+// UseItemOnItem uses the item in slot used on the one in slot target.
+func (p *Player) UseItemOnItem(used, target int) {
+	a, b := p.Inventory.Get(used), p.Inventory.Get(target)
+	if h, ok := uses.onItem[itemPair{a.ID, b.ID}]; ok && used != target {
+		h(p, used, target)
+		return
+	}
+	nothingInteresting()
+}
+
+// UseItemOnTile uses the item in slot used on the tile at pos.
+func (p *Player) UseItemOnTile(used int, pos Point) {
+	item := p.Inventory.Get(used)
+	if tile := p.Map.GetTile(pos.X, pos.Y); tile != nil {
+		if h, ok := uses.onTile[itemTile{item.ID, tile.Type}]; ok {
+			h(p, used, pos)
+			return
+		}
+	}
+	nothingInteresting()
+}
+
+// UseItemOnEnemy uses the item in slot used on e.
+func (p *Player) UseItemOnEnemy(used int, e *Enemy) {
+	item := p.Inventory.Get(used)
+	if h, ok := uses.onEnemy[itemEnemy{item.ID, e.Name}]; ok {
+		h(p, used, e)
+		return
+	}
+	nothingInteresting()
+}
+
+// chooseRecipe makes recipes[0], or asks which to make if there are several.
+func (p *Player) chooseRecipe(recipes []Recipe) {
+	if len(recipes) == 1 {
+		p.Actions.Replace(NewCraftAction(p, recipes[0], makeQuantity, Point{}))
+		return
+	}
+
+	var opts []MenuOption
+	for _, r := range recipes {
+		opts = append(opts, MenuOption{"Make", r.Output.Def().Name, func() {
+			p.Actions.Replace(NewCraftAction(p, r, makeQuantity, Point{}))
+		}})
+	}
+	openContextMenu(rl.GetMousePosition(), opts)
+}
+
+func newUseTable() *UseTable {
+	u := &UseTable{
+		onItem:  map[itemPair]ItemOnItem{},
+		onTile:  map[itemTile]ItemOnTile{},
+		onEnemy: map[itemEnemy]ItemOnEnemy{},
+	}
+
+	// Tinderbox on logs.
+	for _, b := range burnables {
+		u.Item("tinderbox", b.Logs, func(p *Player, _, logs int) { p.TryLightFire(logs) })
+	}
+
+	// Raw food on a fire or range.
+	for _, c := range cookables {
+		for _, source := range []int{TileFire, TileRange} {
+			u.Tile(c.Raw, source, func(p *Player, _ int, pos Point) { p.TryCookAt(pos.X, pos.Y) })
+		}
+	}
+
+	// Hand-made recipes: a tool on the material, or one material on the
+	// other. When a pair makes several things the player picks one.
+	byPair := map[itemPair][]Recipe{}
+	var pairs []itemPair
+	for _, r := range Recipes {
+		if r.Station != StationNone || len(r.Inputs) == 0 {
+			continue
+		}
+		var pair itemPair
+		switch {
+		case len(r.Tools) > 0:
+			pair = itemPair{r.Tools[0], r.Inputs[0].ID}
+		case len(r.Inputs) > 1:
+			pair = itemPair{r.Inputs[0].ID, r.Inputs[1].ID}
+		default:
+			continue
+		}
+		if byPair[pair] == nil {
+			pairs = append(pairs, pair)
+		}
+		byPair[pair] = append(byPair[pair], r)
+	}
+	for _, pair := range pairs {
+		recipes := byPair[pair]
+		u.Item(pair.Used, pair.Target, func(p *Player, _, _ int) { p.chooseRecipe(recipes) })
+	}
+
+	// Ore on a furnace, bars on an anvil.
+	for _, r := range Recipes {
+		if r.Station == StationNone {
+			continue
+		}
+		for _, in := range r.Inputs {
+			u.Tile(in.ID, stationTiles[r.Station], func(p *Player, _ int, pos Point) { p.OpenStation(pos.X, pos.Y) })
+		}
+	}
+
+	// Seeds, compost and farming tools on a patch.
+	tend := func(p *Player, _ int, pos Point) { p.TendPatchAt(pos.X, pos.Y) }
+	for _, c := range crops {
+		u.Tile(c.Seed, TileFarmPatch, tend)
+	}
+	for _, c := range composts {
+		if c.Item != "" {
+			u.Tile(c.Item, TileFarmPatch, tend)
+		}
+	}
+	for _, tool := range []ItemID{"rake", "seed_dibber", "spade", "watering_can"} {
+		u.Tile(tool, TileFarmPatch, tend)
+	}
+
+	return u
+}